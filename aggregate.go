@@ -0,0 +1,191 @@
+package tablewriter
+
+import (
+	"math"
+	"reflect"
+
+	// Packages
+	meta "github.com/djthorpe/go-tablewriter/pkg/meta"
+
+	// Namespace imports
+	. "github.com/djthorpe/go-errors"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// An aggregate function over the values in a column
+type aggregate struct {
+	fn    string       // The aggregate function
+	typ   reflect.Type // The type of the column
+	count int          // The number of non-nil values
+	sum   any          // The sum of values, as int64, uint64 or float64
+	limit any          // The minimum or maximum value
+}
+
+// Aggregates for each column, with nil for columns without an aggregate
+type aggregates []*aggregate
+
+///////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	aggSum   = "sum"
+	aggAvg   = "avg"
+	aggMin   = "min"
+	aggMax   = "max"
+	aggCount = "count"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+// newAggregates returns aggregates for fields with a "total" tag, or nil if
+// no fields have the tag
+func newAggregates(fields []meta.Field) (aggregates, error) {
+	var result aggregates
+	for i, field := range fields {
+		if !field.Is("total") {
+			continue
+		}
+		a, err := newAggregate(field.Tuple("total"), field.Type())
+		if err != nil {
+			return nil, ErrBadParameter.Withf("column %q: %v", field.Name(), err)
+		}
+		if result == nil {
+			result = make(aggregates, len(fields))
+		}
+		result[i] = a
+	}
+	return result, nil
+}
+
+// newAggregate returns an aggregate function for a column type
+func newAggregate(fn string, typ reflect.Type) (*aggregate, error) {
+	switch fn {
+	case aggSum, aggAvg:
+		if numberKind(typ.Kind()) == reflect.Invalid {
+			return nil, ErrBadParameter.Withf("cannot %s values of type %v", fn, typ)
+		}
+	case aggMin, aggMax, aggCount:
+		// Any type can be counted or compared
+	default:
+		return nil, ErrBadParameter.Withf("unknown aggregate %q", fn)
+	}
+	return &aggregate{fn: fn, typ: typ}, nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// add a row of values to the aggregates
+func (a aggregates) add(values []any) {
+	for i, agg := range a {
+		if agg != nil {
+			agg.add(values[i])
+		}
+	}
+}
+
+// reset the aggregates
+func (a aggregates) reset() {
+	for _, agg := range a {
+		if agg != nil {
+			agg.count, agg.sum, agg.limit = 0, nil, nil
+		}
+	}
+}
+
+// add a value to the aggregate, ignoring nil values
+func (a *aggregate) add(v any) {
+	rv := deref(reflect.ValueOf(v))
+	if !rv.IsValid() {
+		return
+	}
+	a.count++
+	switch a.fn {
+	case aggSum, aggAvg:
+		switch numberKind(rv.Kind()) {
+		case reflect.Int64:
+			sum, _ := a.sum.(int64)
+			a.sum = sum + rv.Int()
+		case reflect.Uint64:
+			sum, _ := a.sum.(uint64)
+			a.sum = sum + rv.Uint()
+		case reflect.Float64:
+			sum, _ := a.sum.(float64)
+			a.sum = sum + rv.Float()
+		}
+	case aggMin:
+		if a.limit == nil || compare(rv.Interface(), a.limit, false, false) < 0 {
+			a.limit = rv.Interface()
+		}
+	case aggMax:
+		if a.limit == nil || compare(rv.Interface(), a.limit, false, false) > 0 {
+			a.limit = rv.Interface()
+		}
+	}
+}
+
+// value returns the aggregate value. Sums, averages, minimum and maximum
+// values have the type of the column, so averages of integer columns (such
+// as time.Duration) are rounded, and counts are int. Returns nil if there
+// were no values to aggregate
+func (a *aggregate) value() any {
+	if a.fn == aggCount {
+		return a.count
+	}
+	if a.count == 0 {
+		return nil
+	}
+	switch a.fn {
+	case aggSum:
+		return reflect.ValueOf(a.sum).Convert(a.typ).Interface()
+	case aggAvg:
+		avg := toFloat(a.sum) / float64(a.count)
+		if numberKind(a.typ.Kind()) != reflect.Float64 {
+			avg = math.Round(avg)
+		}
+		return reflect.ValueOf(avg).Convert(a.typ).Interface()
+	default:
+		return a.limit
+	}
+}
+
+// field returns the field used to marshal the aggregate value, which is nil
+// for counts since they are not values of the column
+func (a *aggregate) field(field meta.Field) meta.Field {
+	if a.fn == aggCount {
+		return nil
+	}
+	return field
+}
+
+// toFloat converts an int64, uint64 or float64 value to float64
+func toFloat(v any) float64 {
+	switch v := v.(type) {
+	case int64:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	case float64:
+		return v
+	default:
+		return 0
+	}
+}
+
+// numberKind returns reflect.Int64, reflect.Uint64 or reflect.Float64 for
+// numeric kinds, or reflect.Invalid otherwise
+func numberKind(k reflect.Kind) reflect.Kind {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return reflect.Int64
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return reflect.Uint64
+	case reflect.Float32, reflect.Float64:
+		return reflect.Float64
+	default:
+		return reflect.Invalid
+	}
+}
@@ -0,0 +1,157 @@
+package tablewriter
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+
+	// Packages
+	meta "github.com/djthorpe/go-tablewriter/pkg/meta"
+	text "github.com/djthorpe/go-tablewriter/pkg/text"
+
+	// Namespace imports
+	. "github.com/djthorpe/go-errors"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// orderColumns sorts the fields by the "order" tag. Fields without the tag
+// have an order of zero, and fields with the same order retain the order
+// in which they were declared
+func orderColumns(m meta.Struct) error {
+	fields := m.Fields()
+	if !slices.ContainsFunc(fields, func(field meta.Field) bool {
+		return field.Is("order")
+	}) {
+		return nil
+	}
+	slices.SortStableFunc(fields, func(a, b meta.Field) int {
+		return cmp.Compare(intTuple(a, "order"), intTuple(b, "order"))
+	})
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = field.Name()
+	}
+	return m.SetOrder(names...)
+}
+
+// setColumns orders the fields by the names in include, omitting any other
+// fields, and then omits any fields named in exclude
+func setColumns(m meta.Struct, include, exclude []string) error {
+	for _, names := range [][]string{include, exclude} {
+		if err := checkColumns(m, names...); err != nil {
+			return err
+		}
+	}
+	if len(include) > 0 {
+		if err := m.SetOrder(include...); err != nil {
+			return err
+		}
+		for _, field := range m.Fields()[len(include):] {
+			field.SetOmit(true)
+		}
+	}
+	for _, name := range exclude {
+		m.Field(name).SetOmit(true)
+	}
+
+	// Return success
+	return nil
+}
+
+// checkColumns returns an error if any of the named columns do not exist
+func checkColumns(m meta.Struct, names ...string) error {
+	for _, name := range names {
+		if m.Field(name) == nil {
+			return ErrBadParameter.Withf("unknown column %q", name)
+		}
+	}
+
+	// Return success
+	return nil
+}
+
+// fitColumns omits fields until the table fits within the width, dropping
+// the field with the highest "priority" tag first. Fields without a priority
+// (or with a priority of zero) are never dropped. Returns true if the table
+// fits within the width
+func fitColumns(m meta.Struct, width int) bool {
+	fields := m.Fields()
+	total := tableWidth(fields)
+	for total > width {
+		drop := -1
+		for i, field := range fields {
+			if p := intTuple(field, "priority"); p > 0 && (drop < 0 || p >= intTuple(fields[drop], "priority")) {
+				drop = i
+			}
+		}
+		if drop < 0 {
+			return false
+		}
+		fields[drop].SetOmit(true)
+		total -= fieldWidth(fields[drop]) + 1
+		fields = slices.Delete(fields, drop, drop+1)
+	}
+	return true
+}
+
+// fractionWidths returns the widest fraction of the values for each field
+// with the "decimal" tag, so that the decimal points line up in text output.
+// The widths are returned in the order of the fields, and are zero for
+// fields without the tag
+func fractionWidths(o *options, m meta.Struct, iterator meta.Iterator) ([]int, error) {
+	fields := m.Fields()
+	widths := make([]int, len(fields))
+	if !slices.ContainsFunc(fields, func(field meta.Field) bool { return field.Is("decimal") }) {
+		return widths, nil
+	}
+	defer iterator.Reset()
+	for row := iterator.Next(); row != nil; row = iterator.Next() {
+		values, err := m.Values(row)
+		if err != nil {
+			return nil, err
+		}
+		for i, field := range fields {
+			if !field.Is("decimal") {
+				continue
+			}
+			cell, err := cell(o, field, values[i])
+			if err != nil {
+				return nil, err
+			}
+			if width, ok := text.FractionWidth(cell); ok {
+				widths[i] = max(widths[i], width)
+			}
+		}
+	}
+
+	// Return success
+	return widths, nil
+}
+
+// tableWidth returns the width of a row in text output, including
+// delimiters
+func tableWidth(fields []meta.Field) int {
+	total := 1
+	for _, field := range fields {
+		total += fieldWidth(field) + 1
+	}
+	return total
+}
+
+// fieldWidth returns the width of a field in text output
+func fieldWidth(field meta.Field) int {
+	if width := textFormat(field).Width; width > 0 {
+		return width
+	}
+	return text.DefaultWidth
+}
+
+// intTuple returns the integer value of a tuple, or zero
+func intTuple(field meta.Field, name string) int {
+	if v, err := strconv.Atoi(field.Tuple(name)); err == nil {
+		return v
+	}
+	return 0
+}
@@ -0,0 +1,79 @@
+package tablewriter
+
+import (
+	"strconv"
+	"time"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+// Units for compact durations, largest first
+var durationUnits = []struct {
+	d    time.Duration
+	unit string
+}{
+	{365 * 24 * time.Hour, "y"},
+	{24 * time.Hour, "d"},
+	{time.Hour, "h"},
+	{time.Minute, "m"},
+	{time.Second, "s"},
+	{time.Millisecond, "ms"},
+	{time.Microsecond, "µs"},
+	{time.Nanosecond, "ns"},
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// formatDuration formats a duration in a compact form with at most n units,
+// for example "3d4h" or "250ms", truncating any remainder
+func formatDuration(d time.Duration, n int) string {
+	if d == 0 {
+		return "0s"
+	}
+	sign := ""
+	if d < 0 {
+		sign, d = "-", -d
+	}
+	var result string
+	for _, u := range durationUnits {
+		if n == 0 {
+			break
+		}
+		if d >= u.d {
+			result += strconv.FormatInt(int64(d/u.d), 10) + u.unit
+			d %= u.d
+			n--
+		} else if result != "" {
+			// Units must be consecutive
+			n--
+		}
+	}
+	return sign + result
+}
+
+// formatRelative formats a time relative to now, for example "5m ago"
+// or "in 2h", rounding to the unit which is output
+func formatRelative(t, now time.Time) string {
+	d := now.Sub(t)
+	switch {
+	case d > -time.Second && d < time.Second:
+		return "now"
+	case d > 0:
+		return formatDuration(roundDuration(d), 1) + " ago"
+	default:
+		return "in " + formatDuration(roundDuration(-d), 1)
+	}
+}
+
+// roundDuration rounds a positive duration to the largest unit which is
+// not greater than the duration, for example 1h59m is rounded to 2h
+func roundDuration(d time.Duration) time.Duration {
+	for _, u := range durationUnits {
+		if d >= u.d {
+			return d.Round(u.d)
+		}
+	}
+	return d
+}
@@ -0,0 +1,43 @@
+package tablewriter
+
+import (
+	// Packages
+	expr "github.com/djthorpe/go-tablewriter/pkg/expr"
+	meta "github.com/djthorpe/go-tablewriter/pkg/meta"
+
+	// Namespace imports
+	. "github.com/djthorpe/go-errors"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// filterRows retains the rows of the iterator for which the expression
+// evaluates to true
+func filterRows(m meta.Struct, iterator meta.Iterator, filter *expr.Expr) error {
+	var result error
+
+	// Check the column names
+	for _, name := range filter.Names() {
+		if m.Field(name) == nil {
+			return ErrBadParameter.Withf("unknown column %q", name)
+		}
+	}
+
+	// Filter the rows, stopping at the first error
+	iterator.Filter(func(row any) bool {
+		if result != nil {
+			return false
+		}
+		match, err := filter.Eval(func(name string) (any, error) {
+			return m.Field(name).Value(row)
+		})
+		if err != nil {
+			result = err
+		}
+		return match
+	})
+
+	// Return any errors
+	return result
+}
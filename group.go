@@ -0,0 +1,71 @@
+package tablewriter
+
+import (
+	// Packages
+	meta "github.com/djthorpe/go-tablewriter/pkg/meta"
+
+	// Namespace imports
+	. "github.com/djthorpe/go-errors"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Grouping of consecutive rows with the same value in a column
+type grouping struct {
+	field     meta.Field // The column to group by
+	index     int        // The index of the column in the output, or -1
+	subtotals aggregates // Aggregates for each group, or nil
+	key       any        // The value for the current group
+	prev      any        // The value for the previous group
+	rows      int        // The number of rows seen
+	skipped   bool       // Whether rows in the current group are not output
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+// newGrouping returns a grouping for the named column, with aggregates for
+// subtotals if required
+func newGrouping(m meta.Struct, name string, subtotals bool) (*grouping, error) {
+	g := new(grouping)
+	if g.field = m.Field(name); g.field == nil {
+		return nil, ErrBadParameter.Withf("unknown column %q", name)
+	}
+
+	// Set the index of the column in the output
+	g.index = -1
+	for i, field := range m.Fields() {
+		if field == g.field {
+			g.index = i
+		}
+	}
+
+	// Set the subtotals
+	if subtotals {
+		if totals, err := newAggregates(m.Fields()); err != nil {
+			return nil, err
+		} else {
+			g.subtotals = totals
+		}
+	}
+
+	// Return success
+	return g, nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// next sets the group value from a row, and returns true if the row
+// starts a new group
+func (g *grouping) next(row any) (bool, error) {
+	key, err := g.field.Value(row)
+	if err != nil {
+		return false, err
+	}
+	changed := g.rows == 0 || compare(key, g.key, false, false) != 0
+	g.prev, g.key = g.key, key
+	g.rows++
+	return changed, nil
+}
@@ -1,31 +1,120 @@
 package tablewriter
 
 import (
+	"encoding"
 	"encoding/json"
+	"fmt"
+	"math"
 	"reflect"
 	"time"
+
+	// Packages
+	meta "github.com/djthorpe/go-tablewriter/pkg/meta"
+
+	// Namespace imports
+	. "github.com/djthorpe/go-errors"
 )
 
 ///////////////////////////////////////////////////////////////////////////////
 // TYPES
 
+// Marshaller is implemented by types which customize how they are output.
+// Values are output using the first of the following which applies:
+//
+//  1. TableMarshaller, then Marshaller
+//  2. A converter registered for the type with OptRegistry
+//  3. encoding.TextMarshaler
+//  4. fmt.Stringer
+//  5. error
+//  6. encoding/json
+//
+// A field with the "raw" tag skips encoding.TextMarshaler, fmt.Stringer
+// and error. Strings, numbers with formatting tags, time.Time and
+// time.Duration values are formatted before encoding.TextMarshaler
 type Marshaller interface {
 	Marshal() ([]byte, error)
 }
 
+// TableMarshaller is implemented by types which output a different value
+// depending on the output format, and takes precedence over Marshaller.
+// The value returned is output in place of the original value, and can be
+// a string, []byte or any other value which is then marshalled as usual
+type TableMarshaller interface {
+	MarshalTable(format Format, opts MarshalOpts) (any, error)
+}
+
+// MarshalOpts are the options passed to TableMarshaller
+type MarshalOpts struct {
+	// The column name, or empty for aggregate counts
+	Name string
+
+	// The delimiter between fields
+	Delim rune
+
+	// The width of the column for text output, or zero
+	Width int
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // PRIVATE METHODS
 
-// Convert any value to a byte array
-func marshal(v any, unixTime bool, timeLayout string, timeLocal bool) ([]byte, error) {
+// Convert any value to a byte array, using the tags on the field (which
+// may be nil) and the options to format the value
+func marshal(v any, field meta.Field, o *options) ([]byte, error) {
 	// Check for nil
 	if v == nil || (reflect.TypeOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil()) {
 		return nil, nil
 	}
+	// Use table marshaller if implemented, and marshal the value returned
+	if m, ok := v.(TableMarshaller); ok {
+		if v, err := m.MarshalTable(o.format, marshalOpts(field, o)); err != nil {
+			return nil, err
+		} else if b, ok := v.([]byte); ok {
+			return b, nil
+		} else if _, ok := v.(TableMarshaller); ok {
+			return nil, ErrInternalAppError.Withf("MarshalTable: %T returned a TableMarshaller", m)
+		} else {
+			return marshal(v, field, o)
+		}
+	}
 	// Use marshaller if implemented
 	if m, ok := v.(Marshaller); ok {
 		return m.Marshal()
 	}
+	// Use a registered converter for the type, and marshal the value returned
+	if o.registry != nil {
+		if v, exists, err := o.registry.convert(v); err != nil {
+			return nil, err
+		} else if exists {
+			if b, ok := v.([]byte); ok {
+				return b, nil
+			}
+			return marshal(v, field, o)
+		}
+	}
+	// Dereference pointers, so they are output the same as values
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && !ptrMethods(rv.Type()) {
+		return marshal(rv.Elem().Interface(), field, o)
+	}
+	// Convert integer timestamps to time values
+	if field != nil {
+		if t, ok := unixTime(v, field); ok {
+			v = t
+		}
+	}
+	// Format durations
+	if d, ok := v.(time.Duration); ok {
+		if field != nil && field.Is("compact") {
+			return []byte(formatDuration(d, 2)), nil
+		}
+		return []byte(d.String()), nil
+	}
+	// Format numbers with tags
+	if field != nil {
+		if cell, ok := formatNumber(v, field); ok {
+			return []byte(cell), nil
+		}
+	}
 	switch v := v.(type) {
 	case string:
 		// By default, strings are not quoted
@@ -35,17 +124,27 @@ func marshal(v any, unixTime bool, timeLayout string, timeLocal bool) ([]byte, e
 		if v.IsZero() {
 			return nil, nil
 		}
-		if timeLocal {
-			v = v.Local()
+		if o.relative || (field != nil && field.Is("ago")) {
+			return []byte(formatRelative(v, o.now)), nil
 		}
-		return []byte(v.Format(timeLayout)), nil
-	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
-		if unixTime {
-			return marshal(time.Unix(reflect.ValueOf(v).Int(), 0), unixTime, timeLayout, timeLocal)
+		if o.timeLocal {
+			v = v.Local()
 		}
-	default:
-		if isNil(v) {
-			return nil, nil
+		return []byte(v.Format(o.timeLayout)), nil
+	}
+	// Return nil for nil slices and maps
+	if isNil(v) {
+		return nil, nil
+	}
+	// Use text marshaller, stringer or error unless the field has a "raw" tag
+	if field == nil || !field.Is("raw") {
+		switch v := v.(type) {
+		case encoding.TextMarshaler:
+			return v.MarshalText()
+		case fmt.Stringer:
+			return []byte(v.String()), nil
+		case error:
+			return []byte(v.Error()), nil
 		}
 	}
 
@@ -53,6 +152,75 @@ func marshal(v any, unixTime bool, timeLayout string, timeLocal bool) ([]byte, e
 	return json.Marshal(v)
 }
 
+// marshalOpts returns the options passed to TableMarshaller
+func marshalOpts(field meta.Field, o *options) MarshalOpts {
+	opts := MarshalOpts{
+		Delim: o.delim,
+	}
+	if field != nil {
+		opts.Name = field.Name()
+		if o.format == FormatText || o.format == FormatExpanded {
+			opts.Width = fieldWidth(field)
+		}
+	}
+	return opts
+}
+
+// unixTime converts an integer value to a time value in UTC, when the field
+// has a "unixtime", "unixmilli", "unixmicro" or "unixnano" tag. A zero
+// value is converted to the zero time
+func unixTime(v any, field meta.Field) (time.Time, bool) {
+	rv := deref(reflect.ValueOf(v))
+	if !rv.IsValid() {
+		return time.Time{}, false
+	}
+
+	// Get the integer value
+	var n int64
+	switch numberKind(rv.Kind()) {
+	case reflect.Int64:
+		n = rv.Int()
+	case reflect.Uint64:
+		n = int64(min(rv.Uint(), math.MaxInt64))
+	default:
+		return time.Time{}, false
+	}
+
+	// Convert to time
+	var t time.Time
+	switch {
+	case field.Is("unixtime"):
+		t = time.Unix(n, 0)
+	case field.Is("unixmilli"):
+		t = time.UnixMilli(n)
+	case field.Is("unixmicro"):
+		t = time.UnixMicro(n)
+	case field.Is("unixnano"):
+		t = time.Unix(0, n)
+	default:
+		return time.Time{}, false
+	}
+	if n == 0 {
+		return time.Time{}, true
+	}
+	return t.UTC(), true
+}
+
+// ptrMethods returns true if a pointer type implements encoding.TextMarshaler,
+// fmt.Stringer or error and the type it points to does not
+func ptrMethods(t reflect.Type) bool {
+	for _, iface := range []reflect.Type{
+		reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem(),
+		reflect.TypeOf((*fmt.Stringer)(nil)).Elem(),
+		reflect.TypeOf((*error)(nil)).Elem(),
+	} {
+		if t.Implements(iface) && !t.Elem().Implements(iface) {
+			return true
+		}
+	}
+	return false
+}
+
 // isNil returns true if a value is nil (for pointers, slices and maps)
 func isNil(v any) bool {
 	if v == nil {
@@ -0,0 +1,152 @@
+package tablewriter
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	// Packages
+	meta "github.com/djthorpe/go-tablewriter/pkg/meta"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+var (
+	bytesUnits = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+	siUnits    = []string{"", "k", "M", "G", "T", "P", "E"}
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// formatNumber formats a numeric value using the tags on a field, and
+// returns false if the value is not a number or the field has no tags
+// for formatting numbers. The tags are:
+//
+//	bytes        format as binary units, for example "1.5 GiB"
+//	si           format with SI prefixes, for example "12.3k"
+//	percent      multiply by 100 and add a percent sign, for example "12.5%"
+//	thousands    separate thousands with commas, for example "1,234,567"
+//	precision:N  format with N decimal places
+func formatNumber(v any, field meta.Field) (string, bool) {
+	rv := deref(reflect.ValueOf(v))
+	if !rv.IsValid() {
+		return "", false
+	}
+
+	// Get the value as a float, and whether it is an integer
+	var f float64
+	var isInt bool
+	switch numberKind(rv.Kind()) {
+	case reflect.Int64:
+		f, isInt = float64(rv.Int()), true
+	case reflect.Uint64:
+		f, isInt = float64(rv.Uint()), true
+	case reflect.Float64:
+		f = rv.Float()
+	default:
+		return "", false
+	}
+
+	// Get the precision, which is -1 if not set
+	precision := -1
+	if field.Is("precision") {
+		if p, err := strconv.Atoi(field.Tuple("precision")); err == nil && p >= 0 {
+			precision = p
+		}
+	}
+
+	// Format the value
+	switch {
+	case field.Is("bytes"):
+		return formatUnits(f, 1024, bytesUnits, " ", precision), true
+	case field.Is("si"):
+		return formatUnits(f, 1000, siUnits, "", precision), true
+	case field.Is("percent"):
+		if precision < 0 {
+			precision = 1
+		}
+		return formatFloat(f*100, precision, field.Is("thousands")) + "%", true
+	case field.Is("thousands"):
+		if isInt {
+			return formatInt(rv, true), true
+		}
+		return formatFloat(f, precision, true), true
+	case field.Is("precision") && !isInt:
+		return formatFloat(f, precision, false), true
+	}
+
+	// No formatting
+	return "", false
+}
+
+// formatUnits formats a value by dividing by the base until it is less than
+// the base, and appending the unit. Values without a unit prefix are
+// formatted without decimal places. When the value rounds up to the base,
+// the next unit is used, so 1048575 bytes is "1.0 MiB" rather than "1024.0 KiB"
+func formatUnits(f float64, base float64, units []string, sep string, precision int) string {
+	i := 0
+	for math.Abs(f) >= base && i < len(units)-1 {
+		f /= base
+		i++
+	}
+	for {
+		p := precision
+		if i == 0 && f == math.Trunc(f) {
+			p = 0
+		} else if p < 0 {
+			p = 1
+		}
+		v := strconv.FormatFloat(f, 'f', p, 64)
+		if r, err := strconv.ParseFloat(v, 64); err == nil && math.Abs(r) >= base && i < len(units)-1 {
+			f /= base
+			i++
+			continue
+		}
+		return v + sep + units[i]
+	}
+}
+
+// formatFloat formats a float with the precision (or the smallest
+// number of digits necessary when the precision is -1), optionally
+// with thousands separators
+func formatFloat(f float64, precision int, thousands bool) string {
+	v := strconv.FormatFloat(f, 'f', precision, 64)
+	if !thousands {
+		return v
+	}
+	integer, fraction, _ := strings.Cut(v, ".")
+	if fraction != "" {
+		fraction = "." + fraction
+	}
+	return separateThousands(integer) + fraction
+}
+
+// formatInt formats an integer value, optionally with thousands separators
+func formatInt(rv reflect.Value, thousands bool) string {
+	var v string
+	if rv.CanInt() {
+		v = strconv.FormatInt(rv.Int(), 10)
+	} else {
+		v = strconv.FormatUint(rv.Uint(), 10)
+	}
+	if thousands {
+		v = separateThousands(v)
+	}
+	return v
+}
+
+// separateThousands inserts commas between groups of three digits in a
+// string of digits, which may have a leading minus sign
+func separateThousands(v string) string {
+	sign := ""
+	if strings.HasPrefix(v, "-") {
+		sign, v = "-", v[1:]
+	}
+	for i := len(v) - 3; i > 0; i -= 3 {
+		v = v[:i] + "," + v[i:]
+	}
+	return sign + v
+}
@@ -2,9 +2,12 @@ package tablewriter
 
 import (
 	"io"
+	"time"
 
 	// Packages
+	"github.com/djthorpe/go-tablewriter/pkg/expr"
 	"github.com/djthorpe/go-tablewriter/pkg/terminal"
+	"github.com/djthorpe/go-tablewriter/pkg/text"
 
 	// Namespace imports
 	. "github.com/djthorpe/go-errors"
@@ -14,17 +17,43 @@ import (
 // TYPES
 
 type options struct {
-	delim      rune   // Delimiter used to separate fields
-	header     bool   // Whether to output a header
-	null       string // How the nil value is represented in the output
-	timeLayout string // How time values are formatted in the output
-	timeLocal  bool   // Whether time values should be printed in local time
-	width      int    // Suggested width of the table, including delimiters
-	format
+	delim      rune              // Delimiter used to separate fields
+	header     bool              // Whether to output a header
+	footer     bool              // Whether to output a footer with aggregates
+	group      string            // Column to group rows by
+	head       int               // Number of rows to output from the start, when limited
+	tail       int               // Number of rows to output from the end, when limited
+	pageSize   int               // Number of rows on each page of text output
+	pageFooter bool              // Whether to output a page number after each page
+	formFeed   bool              // Whether to output a form feed between pages
+	pager      bool              // Whether to send output through a pager
+	subtotals  bool              // Whether to output subtotals for each group
+	null       string            // How the nil value is represented in the output
+	nulls      map[string]string // How the nil value is represented, by column
+	timeLayout string            // How time values are formatted in the output
+	timeLocal  bool              // Whether time values should be printed in local time
+	relative   bool              // Whether time values are printed relative to now
+	now        time.Time         // The time used for relative time values
+	width      int               // Suggested width of the table, including delimiters
+	columns    []string          // Columns to output, in order
+	exclude    []string          // Columns to exclude from the output
+	sort       []sortKey         // Columns to sort rows by
+	natural    bool              // Whether to sort strings in natural order
+	fold       bool              // Whether to sort strings case-insensitively
+	filter     *expr.Expr        // Expression used to filter rows
+	registry   *Registry         // Converters for types
+	reveal     bool              // Whether to output secret values
+	truncate   text.Truncation   // Which part of text wider than a column is removed
+	marker     string            // Marker which replaces truncated text
+	multiline  bool              // Whether newlines in text output are line breaks
+	escape     text.Escape       // How non-printable runes are output in text
+	format     Format            // The output format
+	phase      int               // Options being applied, zero for New and one for Write
+	phases     map[string]int    // The phase in which each list option was last set
 }
 
-// The output type
-type format uint
+// Format is the output format
+type Format uint
 
 // TableOpt is a function which can be used to set options on a table
 type TableOpt func(*options) error
@@ -33,11 +62,46 @@ type TableOpt func(*options) error
 // CONSTANTS
 
 const (
-	_          format = iota // Default output format
-	formatCSV                // Output as CSV
-	formatText               // Output as text
+	_              Format = iota // Default output format
+	FormatCSV                    // Output as CSV
+	FormatText                   // Output as text
+	FormatExpanded               // Output as text, with each row as a block of lines
+	formatAuto                   // Output as text or expanded text, depending on width
 )
 
+///////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (f Format) String() string {
+	switch f {
+	case FormatCSV:
+		return "csv"
+	case FormatText:
+		return "text"
+	case FormatExpanded:
+		return "expanded"
+	default:
+		return "auto"
+	}
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// replace returns true on the first call for a list option in each phase,
+// so that options passed to Write replace the list set by options passed
+// to New rather than adding to it
+func (o *options) replace(name string) bool {
+	if phase, exists := o.phases[name]; exists && phase == o.phase {
+		return false
+	}
+	if o.phases == nil {
+		o.phases = make(map[string]int)
+	}
+	o.phases[name] = o.phase
+	return true
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // OPTIONS
 
@@ -75,7 +139,7 @@ func OptTableWidth(v int) TableOpt {
 // Output as CSV
 func OptOutputCSV() TableOpt {
 	return func(o *options) error {
-		o.format = formatCSV
+		o.format = FormatCSV
 		o.delim = ','
 		return nil
 	}
@@ -84,7 +148,7 @@ func OptOutputCSV() TableOpt {
 // Output as TSV
 func OptOutputTSV() TableOpt {
 	return func(o *options) error {
-		o.format = formatCSV
+		o.format = FormatCSV
 		o.delim = '\t'
 		return nil
 	}
@@ -93,7 +157,28 @@ func OptOutputTSV() TableOpt {
 // Output as Text
 func OptOutputText() TableOpt {
 	return func(o *options) error {
-		o.format = formatText
+		o.format = FormatText
+		o.delim = '|'
+		return nil
+	}
+}
+
+// Output as Text, with each row as a block of lines with the name and
+// value of each field
+func OptOutputExpanded() TableOpt {
+	return func(o *options) error {
+		o.format = FormatExpanded
+		o.delim = '|'
+		return nil
+	}
+}
+
+// Output as Text when the table fits within the table width, or as
+// expanded Text when it does not. Use with OptTerminalWidth to choose the
+// layout based on the width of the terminal
+func OptOutputAuto() TableOpt {
+	return func(o *options) error {
+		o.format = formatAuto
 		o.delim = '|'
 		return nil
 	}
@@ -107,6 +192,21 @@ func OptNull(v string) TableOpt {
 	}
 }
 
+// Set how the nil value is represented in the output for a column, which
+// takes precedence over the "null" tag on the field and OptNull
+func OptColumnNull(name, v string) TableOpt {
+	return func(o *options) error {
+		if name == "" {
+			return ErrBadParameter.With("OptColumnNull")
+		}
+		if o.nulls == nil {
+			o.nulls = make(map[string]string)
+		}
+		o.nulls[name] = v
+		return nil
+	}
+}
+
 // Set the delimiter between fields, if not set with OptOutput...
 func OptDelimiter(v rune) TableOpt {
 	return func(o *options) error {
@@ -123,3 +223,257 @@ func OptTimeLayout(v string, local bool) TableOpt {
 		return nil
 	}
 }
+
+// Select the columns to output by name, in the order given. Columns which
+// are not named are not output
+func OptColumns(v ...string) TableOpt {
+	return func(o *options) error {
+		if len(v) == 0 {
+			return ErrBadParameter.With("OptColumns")
+		}
+		o.columns = v
+		return nil
+	}
+}
+
+// Exclude columns from the output by name. When passed to Write, the
+// columns replace those passed to New
+func OptExcludeColumns(v ...string) TableOpt {
+	return func(o *options) error {
+		if len(v) == 0 {
+			return ErrBadParameter.With("OptExcludeColumns")
+		}
+		if o.replace("exclude") {
+			o.exclude = nil
+		}
+		o.exclude = append(o.exclude, v...)
+		return nil
+	}
+}
+
+// Sort rows by a column, in ascending or descending order. Call more than
+// once to sort by several columns. When passed to Write, the columns
+// replace those passed to New. When not set, columns with the "sort" tag
+// are used
+func OptSortBy(name string, desc bool) TableOpt {
+	return func(o *options) error {
+		if name == "" {
+			return ErrBadParameter.With("OptSortBy")
+		}
+		if o.replace("sort") {
+			o.sort = nil
+		}
+		o.sort = append(o.sort, sortKey{name: name, desc: desc})
+		return nil
+	}
+}
+
+// Set how strings are compared when sorting rows. When natural is true,
+// runs of digits are compared numerically (so "a2" sorts before "a10")
+// and when fold is true, strings are compared case-insensitively
+func OptSortStrings(natural, fold bool) TableOpt {
+	return func(o *options) error {
+		o.natural = natural
+		o.fold = fold
+		return nil
+	}
+}
+
+// Filter rows with an expression, such as `status == "failed" && retries > 3`.
+// Identifiers in the expression are column names, which are compared with
+// string, number, boolean and nil literals. Time values can be compared
+// with strings in RFC3339 or "2006-01-02" format
+func OptFilter(v string) TableOpt {
+	return func(o *options) error {
+		if filter, err := expr.Parse(v); err != nil {
+			return ErrBadParameter.Withf("OptFilter: %v", err)
+		} else {
+			o.filter = filter
+		}
+		return nil
+	}
+}
+
+// Output a footer row with the aggregate values for columns with a "total"
+// tag, for example `writer:",total:sum"`. The aggregate functions are sum,
+// avg, min, max and count
+func OptFooter() TableOpt {
+	return func(o *options) error {
+		o.footer = true
+		return nil
+	}
+}
+
+// Group consecutive rows with the same value in a column. In text output,
+// repeated values in the column are left blank. When subtotals is true,
+// a row with the aggregate values for columns with a "total" tag is output
+// after each group
+func OptGroupBy(name string, subtotals bool) TableOpt {
+	return func(o *options) error {
+		if name == "" {
+			return ErrBadParameter.With("OptGroupBy")
+		}
+		o.group = name
+		o.subtotals = subtotals
+		return nil
+	}
+}
+
+// Output only the first n rows. In text output, a row indicates how many
+// rows were not output
+func OptLimit(n int) TableOpt {
+	return func(o *options) error {
+		if n <= 0 {
+			return ErrBadParameter.With("OptLimit")
+		}
+		o.head, o.tail = n, 0
+		return nil
+	}
+}
+
+// Output only the last n rows. In text output, a row indicates how many
+// rows were not output
+func OptLimitTail(n int) TableOpt {
+	return func(o *options) error {
+		if n <= 0 {
+			return ErrBadParameter.With("OptLimitTail")
+		}
+		o.head, o.tail = 0, n
+		return nil
+	}
+}
+
+// Output only the first n and last n rows. In text output, a row indicates
+// how many rows were not output
+func OptLimitHeadTail(n int) TableOpt {
+	return func(o *options) error {
+		if n <= 0 {
+			return ErrBadParameter.With("OptLimitHeadTail")
+		}
+		o.head, o.tail = n, n
+		return nil
+	}
+}
+
+// Break text output into pages of n rows, repeating the header at the top
+// of each page
+func OptPageSize(n int) TableOpt {
+	return func(o *options) error {
+		if n <= 0 {
+			return ErrBadParameter.With("OptPageSize")
+		}
+		o.pageSize = n
+		return nil
+	}
+}
+
+// Output the page number, for example "Page 2/7", at the end of each page
+// when the text output is paginated with OptPageSize
+func OptPageFooter() TableOpt {
+	return func(o *options) error {
+		o.pageFooter = true
+		return nil
+	}
+}
+
+// Output a form feed between pages when the text output is paginated
+// with OptPageSize
+func OptPageFormFeed() TableOpt {
+	return func(o *options) error {
+		o.formFeed = true
+		return nil
+	}
+}
+
+// Send the output through a pager when the output is a terminal and the
+// output is longer than the height of the terminal. The pager command is
+// set by the PAGER environment variable, or is "less -SRFX" by default
+func OptPager() TableOpt {
+	return func(o *options) error {
+		o.pager = true
+		return nil
+	}
+}
+
+// Output all time values relative to the current time, for example "5m ago"
+// or "in 2h". Fields with the "ago" tag are always output relative to the
+// current time
+func OptRelativeTime() TableOpt {
+	return func(o *options) error {
+		o.relative = true
+		return nil
+	}
+}
+
+// Set the current time used for relative time values, which is the time
+// when the table is written by default
+func OptNow(now time.Time) TableOpt {
+	return func(o *options) error {
+		if now.IsZero() {
+			return ErrBadParameter.With("OptNow")
+		}
+		o.now = now
+		return nil
+	}
+}
+
+// Set the registry of converters used to output values of registered types
+func OptRegistry(r *Registry) TableOpt {
+	return func(o *options) error {
+		if r == nil {
+			return ErrBadParameter.With("OptRegistry")
+		}
+		o.registry = r
+		return nil
+	}
+}
+
+// Output the values of fields with the "secret", "mask" or "hash" tags,
+// rather than redacting them
+func OptRevealSecrets() TableOpt {
+	return func(o *options) error {
+		o.reveal = true
+		return nil
+	}
+}
+
+// Output newlines in values as line breaks within the cell in text output,
+// rather than escaping them. Use the "multiline" tag to set this for a column
+func OptMultiline() TableOpt {
+	return func(o *options) error {
+		o.multiline = true
+		return nil
+	}
+}
+
+// Set how non-printable runes (such as control, zero-width and bidirectional
+// formatting characters) and invalid UTF-8 are output in text output. The
+// policy is "escape" (the default, for example "\u200b"), "replace" (with
+// U+FFFD), "strip" or "none", which outputs them as-is
+func OptEscape(policy string) TableOpt {
+	return func(o *options) error {
+		if escape, ok := escaping(policy); !ok {
+			return ErrBadParameter.Withf("OptEscape: %q", policy)
+		} else {
+			o.escape = escape
+		}
+		return nil
+	}
+}
+
+// Set how text which is wider than a column is truncated in text output.
+// The mode is "end", "start" or "middle" (which is useful for long paths
+// and identifiers) and the marker, such as "…" or "...", replaces the text
+// which is removed. Use the "truncate" and "ellipsis" tags to set the
+// truncation for a column
+func OptTruncate(mode, marker string) TableOpt {
+	return func(o *options) error {
+		if truncate, ok := truncation(mode); !ok {
+			return ErrBadParameter.Withf("OptTruncate: %q", mode)
+		} else {
+			o.truncate = truncate
+			o.marker = marker
+		}
+		return nil
+	}
+}
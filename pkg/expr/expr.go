@@ -0,0 +1,345 @@
+package expr
+
+import (
+	"cmp"
+	"encoding"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	// Namespace imports
+	. "github.com/djthorpe/go-errors"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Expr is a parsed expression, which evaluates to true or false
+type Expr struct {
+	root  node
+	names []string
+}
+
+// Scope returns the value for a named identifier in an expression
+type Scope func(name string) (any, error)
+
+type node interface {
+	eval(Scope) (any, error)
+	String() string
+}
+
+type literal struct {
+	value any
+}
+
+type ident struct {
+	name string
+}
+
+type unary struct {
+	op string
+	x  node
+}
+
+type binary struct {
+	op   string
+	x, y node
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+// Layouts which are used to parse strings compared with time values
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	time.DateTime,
+	time.DateOnly,
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (e *Expr) String() string {
+	return e.root.String()
+}
+
+func (n literal) String() string {
+	switch v := n.value.(type) {
+	case nil:
+		return "nil"
+	case string:
+		return fmt.Sprintf("%q", v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func (n ident) String() string {
+	return n.name
+}
+
+func (n unary) String() string {
+	return n.op + n.x.String()
+}
+
+func (n binary) String() string {
+	return "(" + n.x.String() + " " + n.op + " " + n.y.String() + ")"
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Return the identifiers used in the expression, in the order they
+// first appear
+func (e *Expr) Names() []string {
+	return e.names
+}
+
+// Evaluate the expression, using the scope to return values for
+// identifiers. Returns an error if the expression does not evaluate
+// to a boolean value
+func (e *Expr) Eval(scope Scope) (bool, error) {
+	v, err := e.root.eval(scope)
+	if err != nil {
+		return false, err
+	}
+	if b, ok := v.(bool); !ok {
+		return false, ErrBadParameter.Withf("%v: not a boolean expression", e)
+	} else {
+		return b, nil
+	}
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (n literal) eval(Scope) (any, error) {
+	return n.value, nil
+}
+
+func (n ident) eval(scope Scope) (any, error) {
+	return scope(n.name)
+}
+
+func (n unary) eval(scope Scope) (any, error) {
+	x, err := evalBool(n.x, scope)
+	if err != nil {
+		return nil, err
+	}
+	return !x, nil
+}
+
+func (n binary) eval(scope Scope) (any, error) {
+	switch n.op {
+	case "&&", "||":
+		x, err := evalBool(n.x, scope)
+		if err != nil {
+			return nil, err
+		}
+		if (n.op == "&&" && !x) || (n.op == "||" && x) {
+			return x, nil
+		}
+		return evalBool(n.y, scope)
+	}
+
+	// Comparison operators
+	x, err := n.x.eval(scope)
+	if err != nil {
+		return nil, err
+	}
+	y, err := n.y.eval(scope)
+	if err != nil {
+		return nil, err
+	}
+	return compare(n.op, x, y)
+}
+
+// evalBool evaluates a node which should return a boolean value
+func evalBool(n node, scope Scope) (bool, error) {
+	v, err := n.eval(scope)
+	if err != nil {
+		return false, err
+	}
+	if b, ok := deref(v).(bool); ok {
+		return b, nil
+	}
+	return false, ErrBadParameter.Withf("%v: not a boolean value", n)
+}
+
+// compare two values with a comparison operator
+func compare(op string, x, y any) (bool, error) {
+	// Compare strings with the text of values such as enums, which are
+	// output as text
+	if isString(y) && !isString(deref(x)) {
+		if text, ok := textValue(x); ok {
+			x = text
+		}
+	} else if isString(x) && !isString(deref(y)) {
+		if text, ok := textValue(y); ok {
+			y = text
+		}
+	}
+	x, y = deref(x), deref(y)
+
+	// Nil values are only equal to other nil values, and cannot be ordered
+	if x == nil || y == nil {
+		switch op {
+		case "==":
+			return x == y, nil
+		case "!=":
+			return x != y, nil
+		default:
+			return false, nil
+		}
+	}
+
+	// Parse strings which are compared with time values
+	if isTime(x) && isString(y) {
+		if t, err := parseTime(reflect.ValueOf(y).String()); err != nil {
+			return false, err
+		} else {
+			y = t
+		}
+	} else if isString(x) && isTime(y) {
+		if t, err := parseTime(reflect.ValueOf(x).String()); err != nil {
+			return false, err
+		} else {
+			x = t
+		}
+	}
+
+	// Compare values by type
+	var c int
+	switch {
+	case isTime(x) && isTime(y):
+		c = x.(time.Time).Compare(y.(time.Time))
+	case isBool(x) && isBool(y):
+		switch op {
+		case "==":
+			return reflect.ValueOf(x).Bool() == reflect.ValueOf(y).Bool(), nil
+		case "!=":
+			return reflect.ValueOf(x).Bool() != reflect.ValueOf(y).Bool(), nil
+		default:
+			return false, ErrBadParameter.Withf("cannot compare boolean values with %q", op)
+		}
+	case isString(x) && isString(y):
+		c = strings.Compare(reflect.ValueOf(x).String(), reflect.ValueOf(y).String())
+	case isNumber(x) && isNumber(y):
+		c = compareNumber(reflect.ValueOf(x), reflect.ValueOf(y))
+	default:
+		return false, ErrBadParameter.Withf("cannot compare %T with %T", x, y)
+	}
+
+	switch op {
+	case "==":
+		return c == 0, nil
+	case "!=":
+		return c != 0, nil
+	case "<":
+		return c < 0, nil
+	case "<=":
+		return c <= 0, nil
+	case ">":
+		return c > 0, nil
+	case ">=":
+		return c >= 0, nil
+	default:
+		return false, ErrBadParameter.Withf("unknown operator %q", op)
+	}
+}
+
+// compareNumber compares two numeric values, using integer comparison
+// when both values are integers of the same signedness
+func compareNumber(x, y reflect.Value) int {
+	switch {
+	case x.CanInt() && y.CanInt():
+		return cmp.Compare(x.Int(), y.Int())
+	case x.CanUint() && y.CanUint():
+		return cmp.Compare(x.Uint(), y.Uint())
+	default:
+		return cmp.Compare(toFloat(x), toFloat(y))
+	}
+}
+
+func toFloat(v reflect.Value) float64 {
+	switch {
+	case v.CanInt():
+		return float64(v.Int())
+	case v.CanUint():
+		return float64(v.Uint())
+	default:
+		return v.Float()
+	}
+}
+
+// parseTime parses a string as a time value
+func parseTime(v string) (time.Time, error) {
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, ErrBadParameter.Withf("cannot parse %q as a time", v)
+}
+
+// deref dereferences pointers, returning nil for nil pointers
+func deref(v any) any {
+	if v == nil {
+		return nil
+	}
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	return rv.Interface()
+}
+
+// textValue returns the text of a value which implements
+// encoding.TextMarshaler, fmt.Stringer or error, and returns false for
+// other values, nil values and time values
+func textValue(v any) (string, bool) {
+	if d := deref(v); d == nil || isTime(d) {
+		return "", false
+	}
+	switch v := v.(type) {
+	case encoding.TextMarshaler:
+		if text, err := v.MarshalText(); err == nil {
+			return string(text), true
+		}
+	case fmt.Stringer:
+		return v.String(), true
+	case error:
+		return v.Error(), true
+	}
+	return "", false
+}
+
+func isTime(v any) bool {
+	_, ok := v.(time.Time)
+	return ok
+}
+
+func isBool(v any) bool {
+	return reflect.ValueOf(v).Kind() == reflect.Bool
+}
+
+func isString(v any) bool {
+	return reflect.ValueOf(v).Kind() == reflect.String
+}
+
+func isNumber(v any) bool {
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	case reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,139 @@
+package expr_test
+
+import (
+	"testing"
+	"time"
+
+	// Packages
+	expr "github.com/djthorpe/go-tablewriter/pkg/expr"
+	assert "github.com/stretchr/testify/assert"
+
+	// Namespace imports
+	. "github.com/djthorpe/go-errors"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TEST CASES
+
+func Test_expr_000(t *testing.T) {
+	assert := assert.New(t)
+	e, err := expr.Parse(`status == "failed" && retries > 3`)
+	assert.NoError(err)
+	assert.NotNil(e)
+	assert.Equal([]string{"status", "retries"}, e.Names())
+	assert.Equal(`((status == "failed") && (retries > 3))`, e.String())
+}
+
+func Test_expr_001(t *testing.T) {
+	assert := assert.New(t)
+	for _, v := range []string{
+		``, `a ==`, `(a == 1`, `a == "b`, `a # b`, `a == 1 b`, `- == 1`,
+	} {
+		_, err := expr.Parse(v)
+		assert.Error(err, v)
+	}
+}
+
+func Test_expr_002(t *testing.T) {
+	assert := assert.New(t)
+	retries := uint(5)
+	values := map[string]any{
+		"status":  "failed",
+		"retries": &retries,
+		"ratio":   0.5,
+		"enabled": true,
+		"note":    (*string)(nil),
+		"created": time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+	}
+	scope := func(name string) (any, error) {
+		if v, exists := values[name]; exists {
+			return v, nil
+		}
+		return nil, ErrNotFound.With(name)
+	}
+	tests := map[string]bool{
+		`status == "failed" && retries > 3`:  true,
+		`status != "failed" || retries < 3`:  false,
+		`!(retries >= 5)`:                    false,
+		`ratio < 1 && ratio >= -0.5`:         true,
+		`enabled`:                            true,
+		`enabled == false`:                   false,
+		`note == nil`:                        true,
+		`note != nil || retries == 5`:        true,
+		`created > "2024-01-01"`:             true,
+		`created < "2024-05-01T00:00:00Z"`:   false,
+		`status == "ok" && missing == 1`:     false,
+		`(status == "ok") || (ratio == 0.5)`: true,
+	}
+	for v, expected := range tests {
+		e, err := expr.Parse(v)
+		if !assert.NoError(err, v) {
+			continue
+		}
+		result, err := e.Eval(scope)
+		assert.NoError(err, v)
+		assert.Equal(expected, result, v)
+	}
+}
+
+func Test_expr_003(t *testing.T) {
+	assert := assert.New(t)
+	scope := func(name string) (any, error) {
+		return "value", nil
+	}
+	for _, v := range []string{
+		`a`, `a > 1`, `a == true`, `missing`,
+	} {
+		e, err := expr.Parse(v)
+		if !assert.NoError(err, v) {
+			continue
+		}
+		_, err = e.Eval(scope)
+		assert.Error(err, v)
+	}
+}
+
+type Status int
+
+func (s Status) String() string {
+	switch s {
+	case 1:
+		return "failed"
+	default:
+		return "ok"
+	}
+}
+
+func Test_expr_004(t *testing.T) {
+	assert := assert.New(t)
+	status := Status(1)
+	values := map[string]any{
+		"status": status,
+		"ptr":    &status,
+		"none":   (*Status)(nil),
+	}
+	scope := func(name string) (any, error) {
+		return values[name], nil
+	}
+
+	// Values which implement fmt.Stringer are compared with strings as text,
+	// and with numbers as values
+	tests := map[string]bool{
+		`status == "failed"`: true,
+		`"ok" != status`:     true,
+		`status < "g"`:       true,
+		`ptr == "failed"`:    true,
+		`status == 1`:        true,
+		`nil == "failed"`:    false,
+		`none == nil`:        true,
+	}
+	for v, expected := range tests {
+		e, err := expr.Parse(v)
+		if !assert.NoError(err, v) {
+			continue
+		}
+		result, err := e.Eval(scope)
+		assert.NoError(err, v)
+		assert.Equal(expected, result, v)
+	}
+}
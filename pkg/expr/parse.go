@@ -0,0 +1,277 @@
+package expr
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	// Namespace imports
+	. "github.com/djthorpe/go-errors"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type tokenKind int
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+	names  []string
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenOperator
+)
+
+// Operators, with longer operators first
+var operators = []string{
+	"&&", "||", "==", "!=", "<=", ">=", "<", ">", "!", "(", ")",
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+// Parse an expression, which can contain identifiers, string literals
+// in double quotes, numbers, the keywords true, false and nil, the
+// comparison operators ==, !=, <, <=, > and >=, the logical operators
+// &&, || and !, and parentheses for grouping
+func Parse(v string) (*Expr, error) {
+	tokens, err := tokenize(v)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse the tokens
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, ErrBadParameter.Withf("unexpected %q at position %d", t.value, t.pos)
+	}
+
+	// Return success
+	return &Expr{root: root, names: p.names}, nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// tokenize splits an expression into tokens
+func tokenize(v string) ([]token, error) {
+	var result []token
+	pos := 0
+FOR_LOOP:
+	for pos < len(v) {
+		r, n := utf8.DecodeRuneInString(v[pos:])
+		switch {
+		case unicode.IsSpace(r):
+			pos += n
+			continue FOR_LOOP
+		case r == '"':
+			end := pos + 1
+			for end < len(v) && v[end] != '"' {
+				if v[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(v) {
+				return nil, ErrBadParameter.Withf("unterminated string at position %d", pos)
+			}
+			value, err := strconv.Unquote(v[pos : end+1])
+			if err != nil {
+				return nil, ErrBadParameter.Withf("invalid string at position %d", pos)
+			}
+			result = append(result, token{tokenString, value, pos})
+			pos = end + 1
+			continue FOR_LOOP
+		case r == '-' || r == '.' || unicode.IsDigit(r):
+			end := pos + n
+			for end < len(v) && (isIdent(rune(v[end])) || v[end] == '.') {
+				end++
+			}
+			result = append(result, token{tokenNumber, v[pos:end], pos})
+			pos = end
+			continue FOR_LOOP
+		case r == '_' || unicode.IsLetter(r):
+			end := pos + n
+			for end < len(v) {
+				r, n := utf8.DecodeRuneInString(v[end:])
+				if !isIdent(r) {
+					break
+				}
+				end += n
+			}
+			result = append(result, token{tokenIdent, v[pos:end], pos})
+			pos = end
+			continue FOR_LOOP
+		}
+		for _, op := range operators {
+			if strings.HasPrefix(v[pos:], op) {
+				result = append(result, token{tokenOperator, op, pos})
+				pos += len(op)
+				continue FOR_LOOP
+			}
+		}
+		return nil, ErrBadParameter.Withf("unexpected %q at position %d", r, pos)
+	}
+
+	// Return success
+	return append(result, token{tokenEOF, "", pos}), nil
+}
+
+func isIdent(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// peek returns the current token
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+// next returns the current token and advances to the next one
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// accept advances to the next token if the current token is one of the
+// operators, and returns the operator
+func (p *parser) accept(ops ...string) (string, bool) {
+	if t := p.peek(); t.kind == tokenOperator && slices.Contains(ops, t.value) {
+		p.pos++
+		return t.value, true
+	}
+	return "", false
+}
+
+// or := and { "||" and }
+func (p *parser) parseOr() (node, error) {
+	x, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept("||")
+		if !ok {
+			return x, nil
+		}
+		y, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		x = binary{op, x, y}
+	}
+}
+
+// and := unary { "&&" unary }
+func (p *parser) parseAnd() (node, error) {
+	x, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept("&&")
+		if !ok {
+			return x, nil
+		}
+		y, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		x = binary{op, x, y}
+	}
+}
+
+// unary := "!" unary | comparison
+func (p *parser) parseUnary() (node, error) {
+	if op, ok := p.accept("!"); ok {
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return unary{op, x}, nil
+	}
+	return p.parseComparison()
+}
+
+// comparison := primary [ op primary ]
+func (p *parser) parseComparison() (node, error) {
+	x, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	if op, ok := p.accept("==", "!=", "<", "<=", ">", ">="); ok {
+		y, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		return binary{op, x, y}, nil
+	}
+	return x, nil
+}
+
+// primary := ident | string | number | "true" | "false" | "nil" | "(" or ")"
+func (p *parser) parsePrimary() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenString:
+		return literal{t.value}, nil
+	case tokenNumber:
+		if v, err := strconv.ParseInt(t.value, 0, 64); err == nil {
+			return literal{v}, nil
+		} else if v, err := strconv.ParseFloat(t.value, 64); err == nil {
+			return literal{v}, nil
+		} else {
+			return nil, ErrBadParameter.Withf("invalid number %q at position %d", t.value, t.pos)
+		}
+	case tokenIdent:
+		switch t.value {
+		case "true":
+			return literal{true}, nil
+		case "false":
+			return literal{false}, nil
+		case "nil", "null":
+			return literal{nil}, nil
+		}
+		if !slices.Contains(p.names, t.value) {
+			p.names = append(p.names, t.value)
+		}
+		return ident{t.value}, nil
+	case tokenOperator:
+		if t.value == "(" {
+			x, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := p.accept(")"); !ok {
+				return nil, ErrBadParameter.Withf("missing ')' at position %d", p.peek().pos)
+			}
+			return x, nil
+		}
+	case tokenEOF:
+		return nil, ErrBadParameter.With("unexpected end of expression")
+	}
+	return nil, ErrBadParameter.Withf("unexpected %q at position %d", t.value, t.pos)
+}
@@ -3,6 +3,7 @@ package meta
 import (
 	"fmt"
 	"reflect"
+	"slices"
 )
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -10,6 +11,7 @@ import (
 
 type iterator struct {
 	slice reflect.Value
+	rows  []int // indexes of the elements, in iteration order
 	index int
 }
 
@@ -23,6 +25,17 @@ type Iterator interface {
 
 	// Reset the iterator to the beginning
 	Reset()
+
+	// Sort the elements with a comparison function, which returns a
+	// negative number when a < b, a positive number when a > b and zero
+	// when a == b. The sort is stable and the underlying values are
+	// not modified. The iterator is reset.
+	Sort(cmp func(a, b any) int)
+
+	// Filter the elements, retaining only those for which the function
+	// returns true. The underlying values are not modified. The iterator
+	// is reset.
+	Filter(fn func(v any) bool)
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -52,7 +65,11 @@ func NewIterator(v any) (Iterator, error) {
 		}
 	}
 
-	// Set the index parameter
+	// Set the rows and index parameters
+	self.rows = make([]int, self.slice.Len())
+	for i := range self.rows {
+		self.rows[i] = i
+	}
 	self.index = 0
 
 	// Return success
@@ -64,7 +81,7 @@ func NewIterator(v any) (Iterator, error) {
 
 func (i iterator) String() string {
 	str := "<iterator"
-	str += fmt.Sprint(" len=", i.slice.Len())
+	str += fmt.Sprint(" len=", len(i.rows))
 	str += fmt.Sprint(" i=", i.index)
 	return str + ">"
 }
@@ -72,22 +89,38 @@ func (i iterator) String() string {
 ///////////////////////////////////////////////////////////////////////////////
 // PUBLIC METHODS
 
-// Return the number of elements
+// Reset the iterator to the beginning
 func (i *iterator) Reset() {
 	i.index = 0
 }
 
 // Return the number of elements
 func (i *iterator) Len() int {
-	return i.slice.Len()
+	return len(i.rows)
 }
 
 // Return the next struct, or nil
 func (i *iterator) Next() any {
-	if i.index >= i.slice.Len() {
+	if i.index >= len(i.rows) {
 		return nil
 	}
-	v := i.slice.Index(i.index).Interface()
+	v := i.slice.Index(i.rows[i.index]).Interface()
 	i.index++
 	return v
 }
+
+// Sort the elements with a comparison function
+func (i *iterator) Sort(cmp func(a, b any) int) {
+	slices.SortStableFunc(i.rows, func(a, b int) int {
+		return cmp(i.slice.Index(a).Interface(), i.slice.Index(b).Interface())
+	})
+	i.Reset()
+}
+
+// Filter the elements with a function
+func (i *iterator) Filter(fn func(v any) bool) {
+	i.rows = slices.DeleteFunc(i.rows, func(row int) bool {
+		return !fn(i.slice.Index(row).Interface())
+	})
+	i.Reset()
+}
@@ -1,6 +1,7 @@
 package meta_test
 
 import (
+	"strings"
 	"testing"
 
 	// Packages
@@ -54,3 +55,20 @@ func Test_iterator_003(t *testing.T) {
 	i4 := iterator.Next()
 	assert.Nil(i4)
 }
+
+func Test_iterator_004(t *testing.T) {
+	assert := assert.New(t)
+	values := []TestAB{{A: "2"}, {A: "3"}, {A: "1"}}
+	iterator, err := meta.NewIterator(values)
+	assert.NoError(err)
+	iterator.Sort(func(a, b any) int {
+		return strings.Compare(a.(TestAB).A, b.(TestAB).A)
+	})
+	assert.Equal("1", iterator.Next().(TestAB).A)
+	assert.Equal("2", iterator.Next().(TestAB).A)
+	assert.Equal("3", iterator.Next().(TestAB).A)
+	assert.Nil(iterator.Next())
+
+	// Underlying values are not modified
+	assert.Equal("2", values[0].A)
+}
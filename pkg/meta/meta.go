@@ -3,6 +3,7 @@ package meta
 import (
 	"fmt"
 	"reflect"
+	"slices"
 	"strings"
 
 	// Namespace imports
@@ -35,6 +36,13 @@ type Struct interface {
 	// Return field metadata
 	Fields() []Field
 
+	// Return a field by name, or nil if the field does not exist
+	Field(name string) Field
+
+	// Reorder the fields so that the named fields appear first, in the
+	// order given. Returns an error if a name does not match a field
+	SetOrder(names ...string) error
+
 	// Return the field values in the correct order
 	Values(v any) ([]any, error)
 }
@@ -50,6 +58,9 @@ type Field interface {
 	// Return the index of the field
 	Index() []int
 
+	// Return the field value from a struct value
+	Value(v any) (any, error)
+
 	// Whether the field has a tag ie, Is("omitempty")
 	Is(name string) bool
 
@@ -155,6 +166,38 @@ func (meta *meta) Fields() []Field {
 	return result
 }
 
+// Return a field by name, or nil if the field does not exist
+func (meta *meta) Field(name string) Field {
+	if f := meta.field(name); f != nil {
+		return f
+	}
+	return nil
+}
+
+// Reorder the fields so that the named fields appear first, in the
+// order given, followed by the remaining fields in their existing order
+func (meta *meta) SetOrder(names ...string) error {
+	fields := make([]*fieldmeta, 0, len(meta.fields))
+	for _, name := range names {
+		f := meta.field(name)
+		if f == nil {
+			return ErrNotFound.Withf("field %q", name)
+		} else if slices.Contains(fields, f) {
+			return ErrDuplicateEntry.Withf("field %q", name)
+		}
+		fields = append(fields, f)
+	}
+	for _, f := range meta.fields {
+		if !slices.Contains(fields, f) {
+			fields = append(fields, f)
+		}
+	}
+	meta.fields = fields
+
+	// Return success
+	return nil
+}
+
 // Return the field values in the correct order. The input value
 // should be a struct
 func (meta *meta) Values(v any) ([]any, error) {
@@ -200,6 +243,26 @@ func (meta *fieldmeta) Index() []int {
 	return meta.index
 }
 
+// Return the field value from a struct value, which may be a pointer
+// to a struct
+func (meta *fieldmeta) Value(v any) (any, error) {
+	if v == nil {
+		return nil, ErrBadParameter.With("nil value")
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, ErrBadParameter.With("not a struct")
+	}
+	fv, err := rv.FieldByIndexErr(meta.index)
+	if err != nil {
+		return nil, ErrBadParameter.Withf("invalid field %q", meta.key)
+	}
+	return fv.Interface(), nil
+}
+
 // Return the type of field (dereferencing pointers)
 func (meta *fieldmeta) Type() reflect.Type {
 	result := meta.field.Type
@@ -251,6 +314,16 @@ func (meta *fieldmeta) Is(name string) bool {
 ///////////////////////////////////////////////////////////////////////////////
 // PRIVATE METHODS
 
+// Return a field by name, or nil
+func (meta *meta) field(name string) *fieldmeta {
+	for _, f := range meta.fields {
+		if f.Name() == name {
+			return f
+		}
+	}
+	return nil
+}
+
 // Returns the type of a value, which is either a slice of structs,
 // an array of structs or a single struct. Returns an error if the
 // type cannot be determined. If the type is a slice or array, then
@@ -93,3 +93,26 @@ func Test_meta_006(t *testing.T) {
 	assert.Equal(1, len(fields)) // G
 	assert.Equal("this is field G", fields[0].Tag("description"))
 }
+
+func Test_meta_007(t *testing.T) {
+	assert := assert.New(t)
+	meta, err := meta.New([]TestABEF{}, "json")
+	assert.NoError(err)
+	assert.NotNil(meta.Field("F"))
+	assert.Nil(meta.Field("Z"))
+
+	assert.NoError(meta.SetOrder("F", "a"))
+	fields := meta.Fields()
+	assert.Equal(4, len(fields))
+	assert.Equal("F", fields[0].Name())
+	assert.Equal("a", fields[1].Name())
+	assert.Equal("b", fields[2].Name())
+	assert.Equal("E", fields[3].Name())
+
+	values, err := meta.Values(TestABEF{TestAB: TestAB{A: "1", B: "2"}, E: "3", F: "4"})
+	assert.NoError(err)
+	assert.Equal([]any{"4", "1", "2", "3"}, values)
+
+	assert.Error(meta.SetOrder("Z"))
+	assert.Error(meta.SetOrder("F", "F"))
+}
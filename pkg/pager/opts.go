@@ -0,0 +1,45 @@
+package pager
+
+import (
+	// Namespace imports
+	. "github.com/djthorpe/go-errors"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type opts struct {
+	cmd    []string
+	height int
+}
+
+// Opt is a function which can be used to set options on the pager
+type Opt func(*opts) error
+
+///////////////////////////////////////////////////////////////////////////////
+// OPTIONS
+
+// Set the pager command and arguments. The default is the value of the
+// PAGER environment variable, or "less -SRFX" if it is not set
+func OptCommand(cmd ...string) Opt {
+	return func(o *opts) error {
+		if len(cmd) == 0 || cmd[0] == "" {
+			return ErrBadParameter.With("OptCommand")
+		}
+		o.cmd = cmd
+		return nil
+	}
+}
+
+// Set the height of the output in lines. Output which exceeds the height is
+// sent to the pager. The default is the height of the terminal, and output
+// is not paged when the output is not a terminal
+func OptHeight(v int) Opt {
+	return func(o *opts) error {
+		if v <= 0 {
+			return ErrBadParameter.With("OptHeight")
+		}
+		o.height = v
+		return nil
+	}
+}
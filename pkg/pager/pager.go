@@ -0,0 +1,158 @@
+package pager
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+
+	// Packages
+	terminal "github.com/djthorpe/go-tablewriter/pkg/terminal"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Writer buffers output until it exceeds the height of the terminal, and
+// then sends the output through a pager command. Output which does not
+// exceed the height, or which is not written to a terminal, is written
+// directly.
+type Writer struct {
+	opts
+	w     io.Writer
+	buf   bytes.Buffer
+	lines int
+	proc  *exec.Cmd
+	stdin io.WriteCloser
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	envPager = "PAGER"
+)
+
+var (
+	defaultCommand = []string{"less", "-SRFX"}
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+// NewWriter returns a pager which writes to w. The Close method should be
+// called to flush the output and wait for the pager to exit
+func NewWriter(w io.Writer, opts ...Opt) (*Writer, error) {
+	writer := new(Writer)
+	writer.w = w
+
+	// Set defaults
+	if cmd := strings.Fields(os.Getenv(envPager)); len(cmd) > 0 {
+		writer.cmd = cmd
+	} else {
+		writer.cmd = defaultCommand
+	}
+	if terminal.IsTerminal(w) {
+		writer.height = terminal.Height(w)
+	}
+
+	// Set options
+	for _, opt := range opts {
+		if err := opt(&writer.opts); err != nil {
+			return nil, err
+		}
+	}
+
+	// Return success
+	return writer, nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Write output, which is buffered until it exceeds the height
+func (w *Writer) Write(data []byte) (int, error) {
+	switch {
+	case w.stdin != nil:
+		// Write to the pager, ignoring errors when the pager has exited
+		if n, err := w.stdin.Write(data); err != nil && !errors.Is(err, syscall.EPIPE) {
+			return n, err
+		}
+		return len(data), nil
+	case w.height <= 0:
+		// Write directly when not paging
+		return w.w.Write(data)
+	}
+
+	// Buffer the output until it exceeds the height, then start the pager
+	w.buf.Write(data)
+	w.lines += bytes.Count(data, []byte("\n"))
+	if w.lines > w.height {
+		if err := w.start(); err != nil {
+			// Fall back to writing directly
+			w.height = 0
+		}
+		if err := w.flush(); err != nil {
+			return 0, err
+		}
+	}
+
+	// Return success
+	return len(data), nil
+}
+
+// Close flushes any buffered output and waits for the pager to exit
+func (w *Writer) Close() error {
+	if w.stdin == nil {
+		return w.flush()
+	}
+	var result error
+	if err := w.stdin.Close(); err != nil {
+		result = errors.Join(result, err)
+	}
+	if err := w.proc.Wait(); err != nil {
+		result = errors.Join(result, err)
+	}
+	w.stdin, w.proc = nil, nil
+
+	// Return any errors
+	return result
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// start the pager command
+func (w *Writer) start() error {
+	cmd := exec.Command(w.cmd[0], w.cmd[1:]...)
+	cmd.Stdout = w.w
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	w.proc, w.stdin = cmd, stdin
+
+	// Return success
+	return nil
+}
+
+// flush the buffered output to the pager, or directly to the output
+func (w *Writer) flush() error {
+	defer w.buf.Reset()
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	if w.stdin != nil {
+		_, err := w.Write(w.buf.Bytes())
+		return err
+	}
+	_, err := w.w.Write(w.buf.Bytes())
+	return err
+}
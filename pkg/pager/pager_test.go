@@ -0,0 +1,76 @@
+package pager_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	// Packages
+	pager "github.com/djthorpe/go-tablewriter/pkg/pager"
+	assert "github.com/stretchr/testify/assert"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TEST CASES
+
+func Test_pager_000(t *testing.T) {
+	assert := assert.New(t)
+	buf := new(strings.Builder)
+
+	// Output is not a terminal, so is written directly
+	writer, err := pager.NewWriter(buf, pager.OptCommand("tr", "a-z", "A-Z"))
+	assert.NoError(err)
+	fmt.Fprintln(writer, "hello")
+	assert.Equal("hello\n", buf.String())
+	assert.NoError(writer.Close())
+}
+
+func Test_pager_001(t *testing.T) {
+	assert := assert.New(t)
+	buf := new(strings.Builder)
+
+	// Output within the height is written on close
+	writer, err := pager.NewWriter(buf, pager.OptCommand("tr", "a-z", "A-Z"), pager.OptHeight(2))
+	assert.NoError(err)
+	fmt.Fprintln(writer, "hello")
+	fmt.Fprintln(writer, "world")
+	assert.Equal("", buf.String())
+	assert.NoError(writer.Close())
+	assert.Equal("hello\nworld\n", buf.String())
+}
+
+func Test_pager_002(t *testing.T) {
+	assert := assert.New(t)
+	buf := new(strings.Builder)
+
+	// Output which exceeds the height is sent to the pager
+	writer, err := pager.NewWriter(buf, pager.OptCommand("tr", "a-z", "A-Z"), pager.OptHeight(2))
+	assert.NoError(err)
+	for _, line := range []string{"a", "b", "c", "d"} {
+		fmt.Fprintln(writer, line)
+	}
+	assert.NoError(writer.Close())
+	assert.Equal("A\nB\nC\nD\n", buf.String())
+}
+
+func Test_pager_003(t *testing.T) {
+	assert := assert.New(t)
+	buf := new(strings.Builder)
+
+	// Output is written directly when the pager cannot be started
+	writer, err := pager.NewWriter(buf, pager.OptCommand("/nonexistent/pager"), pager.OptHeight(1))
+	assert.NoError(err)
+	fmt.Fprintln(writer, "a")
+	fmt.Fprintln(writer, "b")
+	fmt.Fprintln(writer, "c")
+	assert.NoError(writer.Close())
+	assert.Equal("a\nb\nc\n", buf.String())
+}
+
+func Test_pager_004(t *testing.T) {
+	assert := assert.New(t)
+	_, err := pager.NewWriter(new(strings.Builder), pager.OptCommand())
+	assert.Error(err)
+	_, err = pager.NewWriter(new(strings.Builder), pager.OptHeight(0))
+	assert.Error(err)
+}
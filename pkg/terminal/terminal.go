@@ -30,6 +30,17 @@ func Width(w io.Writer) int {
 	}
 }
 
+// Height returns the height of the terminal, or zero
+func Height(w io.Writer) int {
+	if fd := fileDescriptor(w); fd < 0 {
+		return 0
+	} else if _, height, err := term.GetSize(fd); err != nil {
+		return 0
+	} else {
+		return height
+	}
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // PRIVATE METHODS
 
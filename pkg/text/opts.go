@@ -1,10 +1,16 @@
 package text
 
+import (
+	// Namespace imports
+	. "github.com/djthorpe/go-errors"
+)
+
 ///////////////////////////////////////////////////////////////////////////////
 // TYPES
 
 type opts struct {
 	delim  rune
+	width  int
 	format map[int]Format
 }
 
@@ -13,11 +19,39 @@ type Format struct {
 	// The maximum width of the field
 	Width int
 
-	// The alignment of the field (Left or Right)
+	// The alignment of the field (Left, Right, Center or Decimal)
 	Align Alignment
 
+	// The alignment of the header of the field, or zero to use the
+	// alignment of the field
+	HeaderAlign Alignment
+
+	// The width of numbers from the decimal point to the end of the value,
+	// when the alignment is Decimal, so decimal points line up in the field
+	Fraction int
+
 	// Whether to wrap text
 	Wrap bool
+
+	// Whether newlines in text are line breaks, rather than being escaped
+	Multiline bool
+
+	// How non-printable runes are output (EscapeUnicode, EscapeReplace,
+	// EscapeStrip or EscapeNone)
+	Escape Escape
+
+	// The maximum number of lines of text, or zero for no limit.
+	// The last line ends with the marker when lines are removed
+	MaxLines int
+
+	// Which part of the text is removed when it is wider than the field
+	// (TruncateEnd, TruncateStart or TruncateMiddle)
+	Truncate Truncation
+
+	// The marker which replaces text removed by truncation, such as "…",
+	// or empty to truncate the end without a marker (text removed from the
+	// start or middle is marked with "…")
+	Marker string
 }
 
 // Opt is a function which can be used to set options on the text output
@@ -30,6 +64,23 @@ const (
 	_ Alignment = iota
 	Left
 	Right
+	Decimal
+	Center
+)
+
+const (
+	_ Truncation = iota
+	TruncateEnd
+	TruncateStart
+	TruncateMiddle
+)
+
+const (
+	_             Escape = iota
+	EscapeUnicode        // Escape as \t, \x1b, \u200b or \U000e0001
+	EscapeReplace        // Replace with U+FFFD
+	EscapeStrip          // Remove from the text
+	EscapeNone           // Output as-is
 )
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -55,6 +106,19 @@ func OptFormat(format Format, fields ...int) Opt {
 	}
 }
 
+// Set the width of records written with WriteRecord, including the names.
+// When not set, values in records are not truncated unless the field is
+// wrapped
+func OptWidth(width int) Opt {
+	return func(o *opts) error {
+		if width <= 0 {
+			return ErrBadParameter.With("OptWidth")
+		}
+		o.width = width
+		return nil
+	}
+}
+
 // Set the field delimiter, default is '|'
 func OptDelim(delim rune) Opt {
 	return func(o *opts) error {
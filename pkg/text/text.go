@@ -15,18 +15,27 @@ import (
 
 type Writer struct {
 	opts
-	w   io.Writer
-	row [][]string
+	w    io.Writer
+	row  [][]string
+	cols int // number of fields in the last row written
 }
 
 // Text Alignment
 type Alignment int
 
+// Truncation of text which is wider than the field
+type Truncation int
+
+// Escape policy for non-printable runes and invalid UTF-8 in text
+type Escape int
+
 ///////////////////////////////////////////////////////////////////////////////
 // GLOBALS
 
 const (
-	defaultWidth = 20
+	// The default width of a field
+	DefaultWidth = 20
+
 	defaultDelim = '|'
 )
 
@@ -39,7 +48,7 @@ func NewWriter(w io.Writer, opts ...Opt) (*Writer, error) {
 
 	// Set defaults
 	writer.delim = defaultDelim
-	if err := OptFormat(Format{Width: defaultWidth, Align: Left, Wrap: false})(&writer.opts); err != nil {
+	if err := OptFormat(Format{Width: DefaultWidth, Align: Left, Wrap: false})(&writer.opts); err != nil {
 		return nil, err
 	}
 
@@ -57,16 +66,154 @@ func NewWriter(w io.Writer, opts ...Opt) (*Writer, error) {
 ///////////////////////////////////////////////////////////////////////////////
 // PUBLIC METHODS
 
+// Write a row of values
 func (w *Writer) Write(v []string) error {
+	return w.write(v, false)
+}
+
+// WriteHeader writes a row of values, using the header alignment of
+// each field
+func (w *Writer) WriteHeader(v []string) error {
+	return w.write(v, true)
+}
+
+// WriteRecord writes a record as a block of lines, with one or more lines
+// for each name and value, preceded by a heading line with the label
+func (w *Writer) WriteRecord(label string, names, values []string) error {
+	// Determine the width of the names
+	nameWidth := 0
+	for _, name := range names {
+		nameWidth = max(nameWidth, runewidth.StringWidth(quote(name, EscapeUnicode)))
+	}
+
+	// Determine the width of the values, which is the record width less the
+	// width of the names when set, or else the widest field
+	valueWidth := 0
+	if w.width > 0 {
+		valueWidth = max(1, w.width-nameWidth-3)
+	} else {
+		for i := range values {
+			valueWidth = max(valueWidth, w.fieldFormat(i).Width)
+		}
+	}
+
+	// Format each value, truncating values only when there is a record
+	// width or the field is wrapped
+	var lines []string
+	for i, value := range values {
+		f := w.fieldFormat(i)
+		if w.width > 0 {
+			f.Width = valueWidth
+		} else if !f.Wrap {
+			f.Width = max(f.Width, textWidth(value, f))
+		}
+		for y, line := range format(value, f) {
+			name := ""
+			if y == 0 && i < len(names) {
+				name = names[i]
+			}
+			lines = append(lines, format(name, Format{Width: nameWidth, Align: Left})[0]+" "+string(w.delim)+" "+line)
+		}
+	}
+
+	// Write the heading
+	heading := "-[ " + label + " ]"
+	heading += strings.Repeat("-", max(1, nameWidth+3+valueWidth-runewidth.StringWidth(heading)))
+	if _, err := w.w.Write([]byte(heading + "\n")); err != nil {
+		return err
+	}
+
+	// Write the lines
+	for _, line := range lines {
+		if _, err := w.w.Write([]byte(strings.TrimRight(line, " ") + "\n")); err != nil {
+			return err
+		}
+	}
+
+	// Return success
+	return nil
+}
+
+// Span writes a value which spans a number of fields, truncating the
+// value if it is wider than the fields
+func (w *Writer) Span(v string, cols int) error {
+	width := cols - 1
+	for x := 0; x < cols; x++ {
+		width += w.fieldFormat(x).Width
+	}
+	line := string(w.delim) + format(v, Format{Width: width, Align: Left})[0] + string(w.delim) + "\n"
+	_, err := w.w.Write([]byte(line))
+	return err
+}
+
+// Rule writes a horizontal rule, with the same fields as the last row
+// written
+func (w *Writer) Rule() error {
+	var line strings.Builder
+	for x := 0; x < w.cols; x++ {
+		if x == 0 {
+			line.WriteRune(w.delim)
+		}
+		line.WriteString(strings.Repeat("-", w.fieldFormat(x).Width))
+		line.WriteRune(w.delim)
+	}
+	line.WriteRune('\n')
+	_, err := w.w.Write([]byte(line.String()))
+	return err
+}
+
+// Quote returns text with non-printable runes (such as control, zero-width
+// and bidirectional formatting characters) escaped, replaced with U+FFFD,
+// stripped or passed through according to the policy
+func Quote(v string, policy Escape) string {
+	return quote(v, policy)
+}
+
+// FractionWidth returns the width of a number from the decimal point to the
+// end of the value, which includes any suffix such as "%" or " GiB", and
+// returns false if the value does not start with a number. The width is
+// used to align numbers with the Decimal alignment
+func FractionWidth(v string) (int, bool) {
+	// Skip the sign
+	if strings.HasPrefix(v, "-") || strings.HasPrefix(v, "+") {
+		v = v[1:]
+	}
+
+	// Check the value starts with a digit, or a decimal point and a digit
+	if v == "" || !isDigit(v[0]) && (v[0] != '.' || len(v) < 2 || !isDigit(v[1])) {
+		return 0, false
+	}
+
+	// Skip the integer part, which may include thousands separators
+	i := strings.IndexFunc(v, func(r rune) bool {
+		return (r < '0' || r > '9') && r != ','
+	})
+	if i < 0 {
+		return 0, true
+	}
+	return runewidth.StringWidth(v[i:]), true
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// write a row of values, using the header alignment of each field when
+// header is true
+func (w *Writer) write(v []string, header bool) error {
 	// Set capacity of row
 	if cap(w.row) < len(v) {
 		w.row = make([][]string, len(v))
 	}
 
 	// Format each value
+	w.cols = len(v)
 	maxHeight := 0
 	for i, value := range v {
-		w.row[i] = format(value, w.fieldFormat(i))
+		f := w.fieldFormat(i)
+		if header && f.HeaderAlign != 0 {
+			f.Align = f.HeaderAlign
+		}
+		w.row[i] = format(value, f)
 		maxHeight = max(len(w.row[i]), maxHeight)
 	}
 
@@ -90,9 +237,6 @@ func (w *Writer) Write(v []string) error {
 	return nil
 }
 
-///////////////////////////////////////////////////////////////////////////////
-// PRIVATE METHODS
-
 // return the format for a row, falling back to the default as needed
 func (w *Writer) fieldFormat(i int) Format {
 	def := w.format[-1]
@@ -106,31 +250,88 @@ func (w *Writer) fieldFormat(i int) Format {
 		if !f.Wrap {
 			f.Wrap = def.Wrap
 		}
+		if f.Truncate == 0 {
+			f.Truncate = def.Truncate
+		}
+		if f.Marker == "" {
+			f.Marker = def.Marker
+		}
+		if f.Escape == 0 {
+			f.Escape = def.Escape
+		}
+		if !f.Multiline {
+			f.Multiline = def.Multiline
+		}
+		if f.MaxLines == 0 {
+			f.MaxLines = def.MaxLines
+		}
+		if f.HeaderAlign == 0 {
+			f.HeaderAlign = def.HeaderAlign
+		}
+		if f.Fraction == 0 {
+			f.Fraction = def.Fraction
+		}
 		return f
 	}
 	return def
 }
 
+// textWidth returns the width of the widest line of a value when formatted
+func textWidth(v string, f Format) int {
+	lines := []string{strings.TrimSpace(v)}
+	if f.Multiline {
+		lines = strings.Split(strings.ReplaceAll(lines[0], "\r\n", "\n"), "\n")
+	}
+	width := 0
+	for _, line := range lines {
+		width = max(width, runewidth.StringWidth(quote(line, f.Escape)))
+	}
+	return width
+}
+
 // format a text value to a given format and return the lines
 func format(v string, f Format) []string {
-	// Trim spaces from the text and reformat
-	v = strings.TrimSpace(v)
-	if v != "" {
-		v = string(quote(v))
+	// Trim spaces from the text, and split into lines when newlines are
+	// line breaks
+	var paragraphs []string
+	if v = strings.TrimSpace(v); f.Multiline {
+		paragraphs = strings.Split(strings.ReplaceAll(v, "\r\n", "\n"), "\n")
+	} else {
+		paragraphs = []string{v}
 	}
 
-	// Wrap text to width (which needs to be greater than 0)
-	if f.Wrap {
-		v = runewidth.Wrap(v, f.Width)
+	// Escape each line, and wrap text to width
+	var lines []string
+	for _, line := range paragraphs {
+		if line != "" {
+			line = quote(line, f.Escape)
+		}
+		if f.Wrap {
+			lines = append(lines, wrap(line, f.Width)...)
+		} else {
+			lines = append(lines, line)
+		}
 	}
 
-	// Split lines, truncate and then fill
-	lines := strings.Split(v, "\n")
+	// Limit the number of lines, truncate and then fill
+	lines = limit(lines, f.MaxLines, f.Width, f.Marker)
 	for i, line := range lines {
-		line = runewidth.Truncate(line, f.Width, "")
+		line = truncate(line, f.Width, f.Truncate, f.Marker)
 		switch f.Align {
 		case Left:
 			lines[i] = runewidth.FillRight(line, f.Width)
+		case Decimal:
+			// Pad numbers on the right so the decimal points line up, and
+			// align anything else to the right
+			if fraction, ok := FractionWidth(line); ok {
+				if pad := min(f.Fraction-fraction, f.Width-runewidth.StringWidth(line)); pad > 0 {
+					line += strings.Repeat(" ", pad)
+				}
+			}
+			lines[i] = runewidth.FillLeft(line, f.Width)
+		case Center:
+			pad := max(0, f.Width-runewidth.StringWidth(line))
+			lines[i] = strings.Repeat(" ", pad/2) + line + strings.Repeat(" ", pad-pad/2)
 		default:
 			lines[i] = runewidth.FillLeft(line, f.Width)
 		}
@@ -138,21 +339,87 @@ func format(v string, f Format) []string {
 	return lines
 }
 
-func quote(v string) string {
-	var result string
-	for _, r := range v {
-		result += escapedRune(r)
+// truncate text which is wider than the width, removing text from the end,
+// start or middle and replacing it with the marker. Text removed from the
+// start or middle is always marked, with "…" when there is no marker. The
+// marker is omitted when there is no room for it
+func truncate(v string, width int, mode Truncation, marker string) string {
+	if runewidth.StringWidth(v) <= width {
+		return v
+	}
+	if marker == "" && (mode == TruncateStart || mode == TruncateMiddle) {
+		marker = defaultMarker
+	}
+	if runewidth.StringWidth(marker) >= width {
+		marker = ""
+	}
+	width -= runewidth.StringWidth(marker)
+	switch mode {
+	case TruncateStart:
+		return marker + truncateStart(v, width)
+	case TruncateMiddle:
+		start := runewidth.Truncate(v, width-width/2, "")
+		return start + marker + truncateStart(v, width-runewidth.StringWidth(start))
+	default:
+		return runewidth.Truncate(v, width, "") + marker
 	}
-	return result
 }
 
-func escapedRune(r rune) string {
-	if r == utf8.RuneError || !utf8.ValidRune(r) {
-		return `\uFFFD`
+// truncateStart returns the end of the text which fits within the width
+func truncateStart(v string, width int) string {
+	runes := []rune(v)
+	i := len(runes)
+	for i > 0 && runewidth.RuneWidth(runes[i-1]) <= width {
+		width -= runewidth.RuneWidth(runes[i-1])
+		i--
 	}
-	if strconv.IsPrint(r) {
-		return string(r)
+	return string(runes[i:])
+}
+
+// isDigit returns true if a byte is a decimal digit
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// quote returns text with non-printable runes escaped, replaced, stripped
+// or passed through according to the policy
+func quote(v string, policy Escape) string {
+	var result strings.Builder
+	for len(v) > 0 {
+		r, size := utf8.DecodeRuneInString(v)
+		if r == utf8.RuneError && size == 1 {
+			// Invalid UTF-8
+			switch policy {
+			case EscapeReplace:
+				result.WriteRune(utf8.RuneError)
+			case EscapeStrip:
+				// Do nothing
+			case EscapeNone:
+				result.WriteString(v[:size])
+			default:
+				result.WriteString(fmt.Sprintf(`\x%02x`, v[0]))
+			}
+		} else if strconv.IsPrint(r) {
+			result.WriteRune(r)
+		} else {
+			switch policy {
+			case EscapeReplace:
+				result.WriteRune(utf8.RuneError)
+			case EscapeStrip:
+				// Do nothing
+			case EscapeNone:
+				result.WriteRune(r)
+			default:
+				result.WriteString(escapedRune(r))
+			}
+		}
+		v = v[size:]
 	}
+	return result.String()
+}
+
+// escapedRune returns the escape sequence for a non-printable rune
+func escapedRune(r rune) string {
 	switch r {
 	case '\a':
 		return `\a`
@@ -168,24 +435,13 @@ func escapedRune(r rune) string {
 		return `\t`
 	case '\v':
 		return `\v`
+	}
+	switch {
+	case r < ' ' || r == 0x7f:
+		return fmt.Sprintf(`\x%02x`, r)
+	case r < 0x10000:
+		return fmt.Sprintf(`\u%04x`, r)
 	default:
-		switch {
-		case r < ' ' || r == 0x7f:
-			return fmt.Sprintf(`\x%02x`, r)
-		default:
-			return "TODO"
-			/*		case :
-						return `\uFFFD`
-					case r < 0x10000:
-						buf = append(buf, `\u`...)
-						for s := 12; s >= 0; s -= 4 {
-							buf = append(buf, lowerhex[r>>uint(s)&0xF])
-						}
-					default:
-						buf = append(buf, `\U`...)
-						for s := 28; s >= 0; s -= 4 {
-							buf = append(buf, lowerhex[r>>uint(s)&0xF])
-						}*/
-		}
+		return fmt.Sprintf(`\U%08x`, r)
 	}
 }
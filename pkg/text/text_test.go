@@ -0,0 +1,216 @@
+package text_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"testing/quick"
+	"unicode/utf8"
+
+	// Packages
+	text "github.com/djthorpe/go-tablewriter/pkg/text"
+	assert "github.com/stretchr/testify/assert"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TEST CASES
+
+func Test_text_000(t *testing.T) {
+	assert := assert.New(t)
+	tests := []struct {
+		v     string
+		width int
+		ok    bool
+	}{
+		{"100", 0, true},
+		{"1,234.50", 3, true},
+		{"-1.5", 2, true},
+		{"+.25", 3, true},
+		{"12.5%", 3, true},
+		{"1.5 GiB", 6, true},
+		{"12k", 1, true},
+		{"", 0, false},
+		{"-", 0, false},
+		{".", 0, false},
+		{",5", 0, false},
+		{"n/a", 0, false},
+	}
+	for _, test := range tests {
+		width, ok := text.FractionWidth(test.v)
+		assert.Equal(test.ok, ok, test.v)
+		assert.Equal(test.width, width, test.v)
+	}
+}
+
+func Test_text_001(t *testing.T) {
+	assert := assert.New(t)
+	buf := new(strings.Builder)
+	writer, err := text.NewWriter(buf, text.OptFormat(text.Format{Width: 7, Align: text.Decimal, Fraction: 3}))
+	assert.NoError(err)
+	for _, v := range []string{"1.5", "-12.25", "3", "x"} {
+		assert.NoError(writer.Write([]string{v}))
+	}
+	assert.Equal("|   1.5 |\n| -12.25|\n|   3   |\n|      x|\n", buf.String())
+}
+
+func Test_text_002(t *testing.T) {
+	assert := assert.New(t)
+	buf := new(strings.Builder)
+	writer, err := text.NewWriter(buf, text.OptFormat(text.Format{Width: 7, Align: text.Center, HeaderAlign: text.Left}))
+	assert.NoError(err)
+	assert.NoError(writer.WriteHeader([]string{"name"}))
+	for _, v := range []string{"a", "日本", "abcdefghi"} {
+		assert.NoError(writer.Write([]string{v}))
+	}
+	assert.Equal("|name   |\n|   a   |\n| 日本  |\n|abcdefg|\n", buf.String())
+}
+
+func Test_text_003(t *testing.T) {
+	assert := assert.New(t)
+	tests := []struct {
+		mode   text.Truncation
+		marker string
+		v      string
+		want   string
+	}{
+		{text.TruncateEnd, "", "abcdefghij", "abcdef"},
+		{text.TruncateEnd, "…", "abcdefghij", "abcde…"},
+		{text.TruncateEnd, "...", "abcdefghij", "abc..."},
+		{text.TruncateStart, "…", "abcdefghij", "…fghij"},
+		{text.TruncateStart, "", "abcdefghij", "…fghij"},
+		{text.TruncateMiddle, "", "abcdefghij", "abc…ij"},
+		{text.TruncateMiddle, "…", "abcdefghij", "abc…ij"},
+		{text.TruncateMiddle, "…", "日本語テキスト", " 日…ト"},
+		{text.TruncateEnd, "......", "abcdefghij", "abcdef"},
+		{text.TruncateMiddle, "…", "abcdef", "abcdef"},
+	}
+	for _, test := range tests {
+		buf := new(strings.Builder)
+		writer, err := text.NewWriter(buf, text.OptDelim(' '), text.OptFormat(text.Format{Width: 6, Truncate: test.mode, Marker: test.marker}))
+		assert.NoError(err)
+		assert.NoError(writer.Write([]string{test.v}))
+		assert.Equal(" "+test.want+" \n", buf.String(), test.v)
+	}
+}
+
+func Test_text_004(t *testing.T) {
+	assert := assert.New(t)
+	tests := []struct {
+		v        string
+		maxLines int
+		want     []string
+	}{
+		{"the quick brown fox", 0, []string{"the     ", "quick   ", "brown   ", "fox     "}},
+		{"see https://example.com/a-long_path", 0, []string{"see     ", "https://", "example.", "com/a-  ", "long_   ", "path    "}},
+		{"abcdefghijklmnopqrst", 0, []string{"abcdefg-", "hijklmn-", "opqrst  "}},
+		{"abcdefg.ijklmnop", 0, []string{"abcdefg.", "ijklmnop"}},
+		{"the quick brown fox", 2, []string{"the     ", "quick…  "}},
+		{"abcdefghijklmnopqrst", 2, []string{"abcdefg-", "hijklmn…"}},
+		{"", 2, []string{"        "}},
+	}
+	for _, test := range tests {
+		buf := new(strings.Builder)
+		writer, err := text.NewWriter(buf, text.OptDelim(' '), text.OptFormat(text.Format{Width: 8, Align: text.Left, Wrap: true, MaxLines: test.maxLines}))
+		assert.NoError(err)
+		assert.NoError(writer.Write([]string{test.v}))
+		var want string
+		for _, line := range test.want {
+			want += " " + line + " \n"
+		}
+		assert.Equal(want, buf.String(), test.v)
+	}
+}
+
+func Test_text_005(t *testing.T) {
+	assert := assert.New(t)
+
+	// Newlines are escaped by default
+	buf := new(strings.Builder)
+	writer, err := text.NewWriter(buf, text.OptFormat(text.Format{Width: 8, Align: text.Left}))
+	assert.NoError(err)
+	assert.NoError(writer.Write([]string{"a\nb"}))
+	assert.Equal(`|a\nb    |`+"\n", buf.String())
+
+	// Newlines are line breaks when multiline, and other control characters
+	// are escaped
+	buf.Reset()
+	writer, err = text.NewWriter(buf, text.OptFormat(text.Format{Width: 8, Align: text.Left, Multiline: true, Wrap: true}))
+	assert.NoError(err)
+	assert.NoError(writer.Write([]string{"one\r\ntwo\tthree four", "x"}))
+	assert.Equal("|one     |x       |\n|two\\tth-|        |\n|ree four|        |\n", buf.String())
+}
+
+func Test_text_006(t *testing.T) {
+	assert := assert.New(t)
+
+	// Every rune is output as-is when printable, and otherwise escaped so
+	// that the escape sequence is printable and unquotes to the rune
+	for r := rune(0); r <= utf8.MaxRune; r++ {
+		v := string(r)
+		quoted := text.Quote(v, text.EscapeUnicode)
+		if strconv.IsPrint(r) || !utf8.ValidRune(r) {
+			if quoted != v {
+				assert.Equal(v, quoted, "rune %U", r)
+			}
+			continue
+		}
+		if unquoted, err := strconv.Unquote(`"` + quoted + `"`); err != nil || unquoted != v || !isPrint(quoted) {
+			assert.Fail("escaped rune", "rune %U escaped as %q", r, quoted)
+		}
+	}
+}
+
+func Test_text_007(t *testing.T) {
+	assert := assert.New(t)
+
+	// Non-printable runes are replaced, stripped or passed through
+	for r := rune(0); r <= utf8.MaxRune; r++ {
+		v := string(r)
+		printable := strconv.IsPrint(r) || !utf8.ValidRune(r)
+		replaced, stripped, passed := text.Quote(v, text.EscapeReplace), text.Quote(v, text.EscapeStrip), text.Quote(v, text.EscapeNone)
+		switch {
+		case passed != v:
+			assert.Fail("passed rune", "rune %U passed as %q", r, passed)
+		case printable && (replaced != v || stripped != v):
+			assert.Fail("printable rune", "rune %U output as %q and %q", r, replaced, stripped)
+		case !printable && (replaced != "\uFFFD" || stripped != ""):
+			assert.Fail("non-printable rune", "rune %U output as %q and %q", r, replaced, stripped)
+		}
+	}
+}
+
+func Test_text_008(t *testing.T) {
+	assert := assert.New(t)
+
+	// Any text, including invalid UTF-8, is output as printable text when
+	// escaped, replaced or stripped, and is unchanged when passed through
+	assert.NoError(quick.Check(func(b []byte) bool {
+		v := string(b)
+		escaped, replaced, stripped := text.Quote(v, text.EscapeUnicode), text.Quote(v, text.EscapeReplace), text.Quote(v, text.EscapeStrip)
+		return isPrint(escaped) && isPrint(replaced) && isPrint(stripped) && text.Quote(v, text.EscapeNone) == v
+	}, nil))
+	assert.NoError(quick.Check(func(v string) bool {
+		return isPrint(text.Quote(v, text.EscapeUnicode)) && text.Quote(v, text.EscapeNone) == v
+	}, nil))
+
+	// Examples of escaped text
+	assert.Equal(`a\u200bb\u202e\U000e0001\x1b\xff`, text.Quote("a\u200bb\u202e\U000e0001\x1b\xff", text.EscapeUnicode))
+	assert.Equal("a\uFFFDb\uFFFD", text.Quote("a\u200bb\xff", text.EscapeReplace))
+	assert.Equal("ab", text.Quote("a\u200bb\xff", text.EscapeStrip))
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// isPrint returns true if text is valid UTF-8 and all runes are printable
+func isPrint(v string) bool {
+	if !utf8.ValidString(v) {
+		return false
+	}
+	for _, r := range v {
+		if !strconv.IsPrint(r) {
+			return false
+		}
+	}
+	return true
+}
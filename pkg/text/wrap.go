@@ -0,0 +1,140 @@
+package text
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mattn/go-runewidth"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Characters after which a line can be broken, in addition to spaces
+	breakChars = "/-_"
+
+	// The default marker for text removed by a line limit
+	defaultMarker = "…"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// wrap text to a width, breaking lines at spaces and after the characters
+// in breakChars. Words which are wider than the width are broken at the
+// width, with a hyphen when the break is between letters or digits
+func wrap(v string, width int) []string {
+	if width <= 0 {
+		return []string{v}
+	}
+
+	var lines []string
+	var line strings.Builder
+	lineWidth := 0
+	for _, word := range strings.Fields(v) {
+		for i, part := range splitAfter(word) {
+			// Add a space between words, but not between parts of a word
+			sep := 0
+			if i == 0 && lineWidth > 0 {
+				sep = 1
+			}
+
+			// Add the part to the line if it fits
+			partWidth := runewidth.StringWidth(part)
+			if lineWidth+sep+partWidth <= width {
+				if sep > 0 {
+					line.WriteByte(' ')
+				}
+				line.WriteString(part)
+				lineWidth += sep + partWidth
+				continue
+			}
+
+			// Start a new line, breaking parts which are wider than the width
+			if lineWidth > 0 {
+				lines = append(lines, line.String())
+			}
+			for partWidth > width {
+				var head string
+				head, part = hyphenate(part, width)
+				lines = append(lines, head)
+				partWidth = runewidth.StringWidth(part)
+			}
+			line.Reset()
+			line.WriteString(part)
+			lineWidth = partWidth
+		}
+	}
+	if lineWidth > 0 || len(lines) == 0 {
+		lines = append(lines, line.String())
+	}
+
+	// Return the lines
+	return lines
+}
+
+// limit the number of lines, ending the last line with the marker when
+// lines are removed
+func limit(lines []string, n, width int, marker string) []string {
+	if n <= 0 || len(lines) <= n {
+		return lines
+	}
+	if marker == "" {
+		marker = defaultMarker
+	}
+	last := strings.TrimRightFunc(lines[n-1], unicode.IsSpace)
+	if runewidth.StringWidth(last)+runewidth.StringWidth(marker) > width {
+		last = runewidth.Truncate(last, max(0, width-runewidth.StringWidth(marker)), "")
+	}
+	return append(lines[:n-1], last+marker)
+}
+
+// splitAfter splits a word after each of the characters in breakChars
+func splitAfter(word string) []string {
+	var parts []string
+	for {
+		i := strings.IndexAny(word, breakChars)
+		if i < 0 || i == len(word)-1 {
+			return append(parts, word)
+		}
+		parts = append(parts, word[:i+1])
+		word = word[i+1:]
+	}
+}
+
+// hyphenate splits text which is wider than the width into the head which
+// fits within the width and the tail, adding a hyphen to the head when the
+// text is split between letters or digits
+func hyphenate(v string, width int) (string, string) {
+	if width > 1 {
+		head, tail := splitWidth(v, width-1)
+		last, _ := utf8.DecodeLastRuneInString(head)
+		first, _ := utf8.DecodeRuneInString(tail)
+		if isAlphanumeric(last) && isAlphanumeric(first) {
+			return head + "-", tail
+		}
+	}
+	return splitWidth(v, width)
+}
+
+// isAlphanumeric returns true if a rune is a letter or digit
+func isAlphanumeric(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// splitWidth splits text into the head which fits within the width, and
+// the tail. The head has at least one rune, so that the text is always
+// split
+func splitWidth(v string, width int) (string, string) {
+	w := 0
+	for i, r := range v {
+		rw := runewidth.RuneWidth(r)
+		if w+rw > width && i > 0 {
+			return v[:i], v[i:]
+		}
+		w += rw
+	}
+	return v, ""
+}
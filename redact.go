@@ -0,0 +1,52 @@
+package tablewriter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+
+	// Packages
+	meta "github.com/djthorpe/go-tablewriter/pkg/meta"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	secretValue = "****" // Output in place of a secret value
+	maskChars   = 4      // Default number of characters shown by the mask tag
+	hashChars   = 8      // Number of hex characters in a hashed value
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// redact replaces a value using the tags on a field, and returns false if
+// the field has no tags for redacting values. The tags are:
+//
+//	secret   replace the value with "****"
+//	mask:N   replace all but the last N characters with "****"
+//	hash     replace the value with a short hash, which is stable so values
+//	         can be compared between rows and runs
+func redact(v string, field meta.Field) (string, bool) {
+	switch {
+	case field.Is("secret"):
+		return secretValue, true
+	case field.Is("mask"):
+		n, err := strconv.Atoi(field.Tuple("mask"))
+		if err != nil || n < 0 {
+			n = maskChars
+		}
+		// Mask the whole value when it's too short to reveal any of it
+		if runes := []rune(v); len(runes) > n*2 {
+			return secretValue + string(runes[len(runes)-n:]), true
+		}
+		return secretValue, true
+	case field.Is("hash"):
+		sum := sha256.Sum256([]byte(v))
+		return hex.EncodeToString(sum[:])[:hashChars], true
+	}
+
+	// No redaction
+	return v, false
+}
@@ -0,0 +1,83 @@
+package tablewriter
+
+import (
+	"reflect"
+	"sync"
+
+	// Namespace imports
+	. "github.com/djthorpe/go-errors"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Converter converts a value to another value which is then output, such as
+// a string or []byte
+type Converter func(v any) (any, error)
+
+// Registry of converters for types, which is used to customize the output
+// of types which cannot implement Marshaller, such as types from other
+// packages. A registry can be shared between writers with OptRegistry
+type Registry struct {
+	mu         sync.RWMutex
+	converters map[reflect.Type]Converter
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+// NewRegistry returns an empty registry of converters
+func NewRegistry() *Registry {
+	r := new(Registry)
+	r.converters = make(map[reflect.Type]Converter)
+	return r
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Register a converter for a type. Values of the type, or pointers to the
+// type, are converted before output. Returns an error if a converter is
+// already registered for the type
+func (r *Registry) Register(t reflect.Type, fn Converter) error {
+	if t == nil || fn == nil {
+		return ErrBadParameter.With("Register")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.converters[t]; exists {
+		return ErrDuplicateEntry.Withf("converter for %v", t)
+	}
+	r.converters[t] = fn
+
+	// Return success
+	return nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// convert a value with a registered converter, and return false if there
+// is no converter for the type of the value
+func (r *Registry) convert(v any) (any, bool, error) {
+	rv := reflect.ValueOf(v)
+	r.mu.RLock()
+	fn, exists := r.converters[rv.Type()]
+	if !exists && rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+		fn, exists = r.converters[rv.Type()]
+	}
+	r.mu.RUnlock()
+	if !exists {
+		return nil, false, nil
+	}
+
+	// Convert the value, checking the converter does not return the same type
+	result, err := fn(rv.Interface())
+	if err != nil {
+		return nil, true, err
+	} else if result != nil && reflect.TypeOf(result) == rv.Type() {
+		return nil, true, ErrInternalAppError.Withf("converter for %v returned the same type", rv.Type())
+	}
+	return result, true, nil
+}
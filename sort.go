@@ -0,0 +1,205 @@
+package tablewriter
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	// Packages
+	meta "github.com/djthorpe/go-tablewriter/pkg/meta"
+
+	// Namespace imports
+	. "github.com/djthorpe/go-errors"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// A column to sort rows by
+type sortKey struct {
+	name string // The column name
+	desc bool   // Whether to sort in descending order
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// sortKeys returns the columns to sort by. If no columns are set in the
+// options, then the columns with the "sort" tag are used, in column order,
+// including columns which are not output. A tag of "sort:desc" sorts in
+// descending order. This needs to be called before columns are omitted
+func sortKeys(m meta.Struct, keys []sortKey) ([]sortKey, error) {
+	if len(keys) == 0 {
+		for _, field := range m.Fields() {
+			if !field.Is("sort") {
+				continue
+			}
+			switch desc := field.Tuple("sort"); desc {
+			case "", "asc":
+				keys = append(keys, sortKey{name: field.Name()})
+			case "desc":
+				keys = append(keys, sortKey{name: field.Name(), desc: true})
+			default:
+				return nil, ErrBadParameter.Withf("invalid sort tag %q for column %q", desc, field.Name())
+			}
+		}
+	}
+	for _, key := range keys {
+		if m.Field(key.name) == nil {
+			return nil, ErrBadParameter.Withf("unknown column %q", key.name)
+		}
+	}
+
+	// Return success
+	return keys, nil
+}
+
+// sortRows sorts the rows of the iterator by the sort keys
+func sortRows(m meta.Struct, iterator meta.Iterator, keys []sortKey, natural, fold bool) error {
+	var result error
+
+	// Resolve the fields
+	fields := make([]meta.Field, len(keys))
+	for i, key := range keys {
+		fields[i] = m.Field(key.name)
+	}
+
+	// Sort the rows, collecting the first error
+	iterator.Sort(func(a, b any) int {
+		for i, field := range fields {
+			va, err := field.Value(a)
+			if err != nil {
+				result = err
+				return 0
+			}
+			vb, err := field.Value(b)
+			if err != nil {
+				result = err
+				return 0
+			}
+			c := compare(va, vb, natural, fold)
+			if keys[i].desc {
+				c = -c
+			}
+			if c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+
+	// Return any errors
+	return result
+}
+
+// compare two values of the same type. Pointers are dereferenced, and nil
+// values sort before any other value. Numbers are compared numerically,
+// time.Time values chronologically, and other values by their string
+// representation
+func compare(a, b any, natural, fold bool) int {
+	ra, rb := deref(reflect.ValueOf(a)), deref(reflect.ValueOf(b))
+	switch {
+	case !ra.IsValid() && !rb.IsValid():
+		return 0
+	case !ra.IsValid():
+		return -1
+	case !rb.IsValid():
+		return 1
+	}
+
+	// Time values
+	if ta, ok := ra.Interface().(time.Time); ok {
+		if tb, ok := rb.Interface().(time.Time); ok {
+			return ta.Compare(tb)
+		}
+	}
+
+	// Compare by kind
+	if ra.Kind() == rb.Kind() {
+		switch ra.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return cmp.Compare(ra.Int(), rb.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return cmp.Compare(ra.Uint(), rb.Uint())
+		case reflect.Float32, reflect.Float64:
+			return cmp.Compare(ra.Float(), rb.Float())
+		case reflect.Bool:
+			return cmp.Compare(boolInt(ra.Bool()), boolInt(rb.Bool()))
+		case reflect.String:
+			return compareString(ra.String(), rb.String(), natural, fold)
+		}
+	}
+
+	// Fallback to comparing strings
+	return compareString(fmt.Sprint(ra.Interface()), fmt.Sprint(rb.Interface()), natural, fold)
+}
+
+// compareString compares two strings, optionally ignoring case and
+// comparing runs of digits numerically
+func compareString(a, b string, natural, fold bool) int {
+	if fold {
+		a, b = strings.ToLower(a), strings.ToLower(b)
+	}
+	if !natural {
+		return strings.Compare(a, b)
+	}
+	for a != "" && b != "" {
+		ra, na := utf8.DecodeRuneInString(a)
+		rb, nb := utf8.DecodeRuneInString(b)
+		if isDigit(ra) && isDigit(rb) {
+			// Compare runs of digits by length (ignoring leading zeros)
+			// and then lexically
+			da, db := digits(a), digits(b)
+			a, b = a[len(da):], b[len(db):]
+			ta, tb := strings.TrimLeft(da, "0"), strings.TrimLeft(db, "0")
+			if c := cmp.Compare(len(ta), len(tb)); c != 0 {
+				return c
+			}
+			if c := strings.Compare(ta, tb); c != 0 {
+				return c
+			}
+			continue
+		}
+		if c := cmp.Compare(ra, rb); c != 0 {
+			return c
+		}
+		a, b = a[na:], b[nb:]
+	}
+	return cmp.Compare(len(a), len(b))
+}
+
+// digits returns the leading run of digits in a string
+func digits(v string) string {
+	for i, r := range v {
+		if !isDigit(r) {
+			return v[:i]
+		}
+	}
+	return v
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+// deref dereferences pointers and interfaces, returning an invalid value
+// for nil
+func deref(rv reflect.Value) reflect.Value {
+	for rv.IsValid() && (rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface) {
+		if rv.IsNil() {
+			return reflect.Value{}
+		}
+		rv = rv.Elem()
+	}
+	return rv
+}
+
+func boolInt(v bool) int {
+	if v {
+		return 1
+	}
+	return 0
+}
@@ -12,6 +12,7 @@ import (
 
 	// Packages
 	meta "github.com/djthorpe/go-tablewriter/pkg/meta"
+	pager "github.com/djthorpe/go-tablewriter/pkg/pager"
 	text "github.com/djthorpe/go-tablewriter/pkg/text"
 )
 
@@ -20,11 +21,14 @@ import (
 
 // A writer object which can write table data to an io.Writer
 type Writer struct {
-	w    io.Writer
-	opts []TableOpt
-	csv  *csv.Writer
-	text *text.Writer
-	row  []string
+	w      io.Writer
+	opts   []TableOpt
+	csv    *csv.Writer
+	text   *text.Writer
+	row    []string
+	fields []meta.Field // fields which are output
+	names  []string     // field names, for expanded output
+	record int          // record number, for expanded output
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -34,6 +38,7 @@ const (
 	defaultNull       = "<nil>"
 	defaultTimeLayout = time.RFC1123
 	defaultTimeLocal  = false
+	defaultMarker     = "…"
 )
 
 var (
@@ -73,9 +78,7 @@ func (w *Writer) Writeln(v ...any) error {
 
 // Write the table to output, applying any options which override to the
 // options passed to the New method
-func (w *Writer) Write(v any, opts ...TableOpt) error {
-	var result error
-
+func (w *Writer) Write(v any, opts ...TableOpt) (result error) {
 	// Create an iterator
 	iterator, err := meta.NewIterator(v)
 	if err != nil {
@@ -90,13 +93,52 @@ func (w *Writer) Write(v any, opts ...TableOpt) error {
 
 	// Options processing
 	var o options
-	o.format = formatCSV
+	o.format = FormatCSV
 	o.delim = ','
 	o.null = defaultNull
 	o.timeLayout = defaultTimeLayout
 	o.timeLocal = defaultTimeLocal
-	for _, opt := range append(w.opts, opts...) {
-		if err := opt(&o); err != nil {
+	for _, opts := range [][]TableOpt{w.opts, opts} {
+		for _, opt := range opts {
+			if err := opt(&o); err != nil {
+				return err
+			}
+		}
+		o.phase++
+	}
+	if o.now.IsZero() {
+		o.now = time.Now()
+	}
+
+	// Order the columns by tag, and resolve the sort keys before columns
+	// are omitted, so that rows can be sorted by columns which are not output.
+	// Then select and order the columns by option
+	if err := orderColumns(meta); err != nil {
+		return err
+	}
+	keys, err := sortKeys(meta, o.sort)
+	if err != nil {
+		return err
+	}
+	if err := setColumns(meta, o.columns, o.exclude); err != nil {
+		return err
+	}
+	for name := range o.nulls {
+		if err := checkColumns(meta, name); err != nil {
+			return err
+		}
+	}
+
+	// Filter the rows
+	if o.filter != nil {
+		if err := filterRows(meta, iterator, o.filter); err != nil {
+			return err
+		}
+	}
+
+	// Sort the rows
+	if len(keys) > 0 {
+		if err := sortRows(meta, iterator, keys, o.natural, o.fold); err != nil {
 			return err
 		}
 	}
@@ -135,37 +177,127 @@ func (w *Writer) Write(v any, opts ...TableOpt) error {
 		}
 	}
 
+	// Drop low-priority columns which do not fit the table width, and output
+	// as expanded text with all the columns when the table still does not fit
+	switch {
+	case o.format == FormatText && o.width > 0:
+		fitColumns(meta, o.width)
+	case o.format == formatAuto && o.width > 0:
+		fields := meta.Fields()
+		if fitColumns(meta, o.width) {
+			o.format = FormatText
+		} else {
+			for _, field := range fields {
+				field.SetOmit(false)
+			}
+			o.format = FormatExpanded
+		}
+	case o.format == formatAuto:
+		o.format = FormatText
+	}
+
+	// Set the fields which are output
+	w.fields = meta.Fields()
+
+	// Send the output through a pager, restoring the output when done
+	if o.pager {
+		if pager, err := pager.NewWriter(w.w); err != nil {
+			return err
+		} else {
+			defer func(out io.Writer) {
+				w.w = out
+				result = errors.Join(result, pager.Close())
+			}(w.w)
+			w.w = pager
+		}
+	}
+
 	// Create the writer object based on the format required
 	switch o.format {
-	case formatCSV:
+	case FormatCSV:
 		w.csv = csv.NewWriter(w.w)
 		w.csv.Comma = o.delim
-	case formatText:
+	case FormatText, FormatExpanded:
 		opts := []text.Opt{
 			text.OptDelim(o.delim),
 		}
+		if o.format == FormatExpanded && o.width > 0 {
+			opts = append(opts, text.OptWidth(o.width))
+		}
+		fractions, err := fractionWidths(&o, meta, iterator)
+		if err != nil {
+			return err
+		}
 		for i, field := range meta.Fields() {
-			if textFormat := textFormat(field); textFormat.Width > 0 || textFormat.Align != 0 || textFormat.Wrap {
+			textFormat := textFormat(field)
+			textFormat.Fraction = fractions[i]
+			if o.multiline {
+				textFormat.Multiline = true
+			}
+			textFormat.Escape = o.escape
+			if textFormat.Truncate == 0 {
+				textFormat.Truncate = o.truncate
+			}
+			if textFormat.Marker == "" {
+				textFormat.Marker = o.marker
+			}
+			if textFormat != (text.Format{}) {
 				opts = append(opts, text.OptFormat(textFormat, i))
 			}
 		}
-		if o.width > 0 {
-			fmt.Println("TODO: Set width", o.width)
-		}
 		if writer, err := text.NewWriter(w.w, opts...); err != nil {
 			return err
 		} else {
 			w.text = writer
 		}
+		if o.format == FormatExpanded {
+			w.names, w.record = w.names[:0], 0
+			for _, field := range meta.Fields() {
+				w.names = append(w.names, field.Name())
+			}
+		}
 	default:
 		return errUnsupportedFormat
 	}
 
+	// Set the aggregates for the footer
+	var footer aggregates
+	if o.footer {
+		if footer, err = newAggregates(meta.Fields()); err != nil {
+			return err
+		}
+	}
+
+	// Set the grouping column
+	var group *grouping
+	if o.group != "" {
+		if group, err = newGrouping(meta, o.group, o.subtotals); err != nil {
+			return err
+		}
+	}
+
+	// Set the range of rows which are skipped when the output is limited
+	skip, end := 0, 0
+	if o.head > 0 || o.tail > 0 {
+		if end = iterator.Len() - o.tail; end > o.head {
+			skip = o.head
+		} else {
+			end = 0
+		}
+	}
+
+	// Set the number of pages when the text output is paginated
+	page, pages := 1, 0
+	if o.pageSize > 0 && o.format == FormatText {
+		pages = (iterator.Len() - (end - skip) + o.pageSize - 1) / o.pageSize
+	}
+
 	// Write rows
 	header := false
-	for row := iterator.Next(); row != nil; row = iterator.Next() {
+	rows := 0
+	for i, row := 0, iterator.Next(); row != nil; i, row = i+1, iterator.Next() {
 		if !header {
-			if o.header {
+			if o.header && o.format != FormatExpanded {
 				if err := w.writeHeader(o.format, meta); err != nil {
 					result = errors.Join(result, err)
 					break
@@ -173,14 +305,89 @@ func (w *Writer) Write(v any, opts ...TableOpt) error {
 			}
 			header = true
 		}
-		if err := w.writeRow(&o, meta, row); err != nil {
+
+		values, err := meta.Values(row)
+		if err != nil {
+			result = errors.Join(result, err)
+			continue
+		}
+
+		// When the group changes, write the subtotals for the previous group,
+		// unless some of its rows were skipped. In text output, repeated group
+		// values are left blank, except in the row after skipped rows
+		skipped := i >= skip && i < end
+		blank := -1
+		if group != nil {
+			if changed, err := group.next(row); err != nil {
+				result = errors.Join(result, err)
+				continue
+			} else if !changed && o.format == FormatText && i != end {
+				blank = group.index
+			} else if changed {
+				if group.subtotals != nil && group.rows > 1 && !group.skipped {
+					if err := w.writeTotals(&o, group.subtotals, group.index, group.prev); err != nil {
+						result = errors.Join(result, err)
+					}
+				}
+				group.subtotals.reset()
+				group.skipped = false
+			}
+			group.subtotals.add(values)
+			group.skipped = group.skipped || skipped
+		}
+
+		// Aggregate all rows in the footer, including skipped rows
+		footer.add(values)
+
+		// Skip rows, writing a marker in place of the first skipped row
+		if skipped {
+			if i == skip {
+				if err := w.writeMarker(&o, len(meta.Fields()), end-skip); err != nil {
+					result = errors.Join(result, err)
+				}
+			}
+			continue
+		}
+
+		// Start a new page, repeating the header
+		if pages > 0 && rows > 0 && rows%o.pageSize == 0 {
+			if err := w.writePageBreak(&o, meta, page, pages); err != nil {
+				result = errors.Join(result, err)
+			}
+			page++
+			blank = -1
+		}
+
+		if err := w.writeRow(&o, values, blank); err != nil {
+			result = errors.Join(result, err)
+		}
+		rows++
+	}
+
+	// Write subtotals for the last group, unless some of its rows were skipped
+	if header && group != nil && group.subtotals != nil && !group.skipped {
+		if err := w.writeTotals(&o, group.subtotals, group.index, group.key); err != nil {
+			result = errors.Join(result, err)
+		}
+	}
+
+	// Write footer
+	if header && footer != nil {
+		if err := w.writeTotals(&o, footer, -1, nil); err != nil {
+			result = errors.Join(result, err)
+		}
+	}
+
+	// Write the page footer for the last page
+	if header && pages > 0 && o.pageFooter {
+		if err := w.Writeln(fmt.Sprintf("Page %d/%d", page, pages)); err != nil {
 			result = errors.Join(result, err)
 		}
 	}
 
 	// Flush
 	switch o.format {
-	case formatCSV:
+	case FormatCSV:
 		w.csv.Flush()
 		if err := w.csv.Error(); err != nil {
 			result = errors.Join(result, err)
@@ -208,6 +415,42 @@ func textFormat(field meta.Field) text.Format {
 		result.Align = text.Left
 	case field.Is("right"):
 		result.Align = text.Right
+	case field.Is("decimal"):
+		result.Align = text.Decimal
+	case field.Is("center"):
+		result.Align = text.Center
+	}
+
+	// Newlines are line breaks
+	if field.Is("multiline") {
+		result.Multiline = true
+	}
+
+	// Maximum number of lines
+	if field.Is("maxlines") {
+		if n, err := strconv.Atoi(field.Tuple("maxlines")); err == nil && n > 0 {
+			result.MaxLines = n
+		}
+	}
+
+	// Truncation
+	if field.Is("truncate") {
+		result.Truncate, _ = truncation(field.Tuple("truncate"))
+	}
+	if field.Is("ellipsis") {
+		if result.Marker = field.Tuple("ellipsis"); result.Marker == "" {
+			result.Marker = defaultMarker
+		}
+	}
+
+	// Header alignment
+	switch field.Tuple("header-align") {
+	case "left":
+		result.HeaderAlign = text.Left
+	case "right":
+		result.HeaderAlign = text.Right
+	case "center":
+		result.HeaderAlign = text.Center
 	}
 
 	// Width
@@ -219,51 +462,70 @@ func textFormat(field meta.Field) text.Format {
 	return result
 }
 
-func (w *Writer) writeHeader(f format, meta meta.Struct) error {
+// truncation returns the truncation mode for a name, which is "end",
+// "start" or "middle", and returns false if the name is not recognized
+func truncation(name string) (text.Truncation, bool) {
+	switch name {
+	case "end":
+		return text.TruncateEnd, true
+	case "start":
+		return text.TruncateStart, true
+	case "middle":
+		return text.TruncateMiddle, true
+	default:
+		return 0, false
+	}
+}
+
+// escaping returns the escape policy for a name, which is "escape",
+// "replace", "strip" or "none", and returns false if the name is not
+// recognized
+func escaping(name string) (text.Escape, bool) {
+	switch name {
+	case "escape":
+		return text.EscapeUnicode, true
+	case "replace":
+		return text.EscapeReplace, true
+	case "strip":
+		return text.EscapeStrip, true
+	case "none":
+		return text.EscapeNone, true
+	default:
+		return 0, false
+	}
+}
+
+func (w *Writer) writeHeader(f Format, meta meta.Struct) error {
 	fields := meta.Fields()
 	w.row = make([]string, len(fields))
 	for i, field := range fields {
 		w.row[i] = field.Name()
 	}
 
-	// Write header row
-	switch f {
-	case formatCSV:
-		if err := w.csv.Write(w.row); err != nil {
-			return err
-		}
-	case formatText:
-		if err := w.text.Write(w.row); err != nil {
-			return err
-		}
+	// Write header row, with the header alignment for text
+	if f == FormatText {
+		return w.text.WriteHeader(w.row)
 	}
-
-	// Return success
-	return nil
+	return w.writeCells(f)
 }
 
-func (w *Writer) writeRow(o *options, meta meta.Struct, row any) error {
-	values, err := meta.Values(row)
-	if err != nil {
-		return err
-	}
-
+// writeRow writes a row of values, leaving the cell at index blank empty
+// (or -1 to write every cell)
+func (w *Writer) writeRow(o *options, values []any, blank int) error {
 	// Convert values to []string
 	if len(w.row) != len(values) {
 		w.row = make([]string, len(values))
 	}
 
-	// TODO: if a field has unixtime tag, then convert to time.Time
-
 	// Marshal values
 	var result error
 	for i, v := range values {
-		if cell, err := marshal(v, false, o.timeLayout, o.timeLocal); err != nil {
+		if i == blank {
+			w.row[i] = ""
+		} else if cell, err := cell(o, w.fields[i], v); err != nil {
 			result = errors.Join(result, err)
-		} else if cell == nil {
-			w.row[i] = o.null
 		} else {
-			w.row[i] = string(cell)
+			w.row[i] = cell
 		}
 	}
 	if result != nil {
@@ -271,15 +533,132 @@ func (w *Writer) writeRow(o *options, meta meta.Struct, row any) error {
 	}
 
 	// Write row
+	return w.writeCells(o.format)
+}
+
+// writeTotals writes a row of aggregate values, with the value for
+// the cell at index key (or -1 for no key). Cells without an aggregate
+// are left empty
+func (w *Writer) writeTotals(o *options, totals aggregates, key int, value any) error {
+	// Write a rule between the rows and the totals
+	if o.format == FormatText {
+		if err := w.text.Rule(); err != nil {
+			return err
+		}
+	}
+
+	// Marshal the values
+	var result error
+	for i, a := range totals {
+		if i == key {
+			if cell, err := cell(o, w.fields[i], value); err != nil {
+				result = errors.Join(result, err)
+			} else {
+				w.row[i] = cell
+			}
+		} else if a == nil {
+			w.row[i] = ""
+		} else if cell, err := cell(o, a.field(w.fields[i]), a.value()); err != nil {
+			result = errors.Join(result, err)
+		} else {
+			w.row[i] = cell
+		}
+	}
+	if result != nil {
+		return result
+	}
+
+	// Write totals row
+	if o.format == FormatExpanded {
+		return w.text.WriteRecord("TOTAL", w.names, w.row)
+	}
+	return w.writeCells(o.format)
+}
+
+// writePageBreak ends a page, writing the page footer and form feed if
+// enabled, and then repeats the header at the start of the next page
+func (w *Writer) writePageBreak(o *options, meta meta.Struct, page, pages int) error {
+	if o.pageFooter {
+		if err := w.Writeln(fmt.Sprintf("Page %d/%d", page, pages)); err != nil {
+			return err
+		}
+	}
+	if o.formFeed {
+		if _, err := w.w.Write([]byte("\f")); err != nil {
+			return err
+		}
+	}
+	if o.header {
+		return w.writeHeader(o.format, meta)
+	}
+
+	// Return success
+	return nil
+}
+
+// writeMarker writes a marker in place of n rows which are not output. The
+// marker is only written for text output
+func (w *Writer) writeMarker(o *options, cols, n int) error {
+	marker := fmt.Sprintf("… %s more rows", separateThousands(strconv.Itoa(n)))
+	if n == 1 {
+		marker = "… 1 more row"
+	}
 	switch o.format {
-	case formatCSV:
+	case FormatText:
+		return w.text.Span(marker, cols)
+	case FormatExpanded:
+		return w.Writeln(marker)
+	}
+
+	// Return success
+	return nil
+}
+
+// cell marshals a value for a field to a string, using the null value
+// for nil and redacting secret values
+func cell(o *options, field meta.Field, v any) (string, error) {
+	if cell, err := marshal(v, field, o); err != nil {
+		return "", err
+	} else if cell == nil {
+		return nullValue(o, field), nil
+	} else if field != nil && !o.reveal {
+		cell, _ := redact(string(cell), field)
+		return cell, nil
+	} else {
+		return string(cell), nil
+	}
+}
+
+// nullValue returns how the nil value is represented for a field, which
+// is set by OptColumnNull, then the "null" tag, then OptNull
+func nullValue(o *options, field meta.Field) string {
+	if field == nil {
+		return o.null
+	}
+	if v, exists := o.nulls[field.Name()]; exists {
+		return v
+	} else if field.Is("null") {
+		return field.Tuple("null")
+	}
+	return o.null
+}
+
+// writeCells writes the current row of cells to the output
+func (w *Writer) writeCells(f Format) error {
+	switch f {
+	case FormatCSV:
 		if err := w.csv.Write(w.row); err != nil {
 			return err
 		}
-	case formatText:
+	case FormatText:
 		if err := w.text.Write(w.row); err != nil {
 			return err
 		}
+	case FormatExpanded:
+		w.record++
+		if err := w.text.WriteRecord(fmt.Sprint("RECORD ", w.record), w.names, w.row); err != nil {
+			return err
+		}
 	}
 
 	// Return success
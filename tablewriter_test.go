@@ -1,9 +1,15 @@
 package tablewriter_test
 
 import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
 	"os"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/djthorpe/go-tablewriter"
 	"github.com/stretchr/testify/assert"
@@ -83,3 +89,902 @@ func Test_tablewriter_008(t *testing.T) {
 	assert.NoError(err)
 	assert.Equal("NULL\n", buf.String())
 }
+
+func Test_tablewriter_009(t *testing.T) {
+	assert := assert.New(t)
+	buf := new(strings.Builder)
+	writer := tablewriter.New(buf, tablewriter.OptHeader())
+	table := []TestABEF{
+		{TestAB: TestAB{A: "a", B: "b"}, E: "e", F: "f"},
+	}
+	err := writer.Write(table, tablewriter.OptColumns("F", "a", "E"))
+	assert.NoError(err)
+	assert.Equal("F,a,E\nf,a,e\n", buf.String())
+}
+
+func Test_tablewriter_010(t *testing.T) {
+	assert := assert.New(t)
+	buf := new(strings.Builder)
+	writer := tablewriter.New(buf, tablewriter.OptHeader())
+	table := []TestABEF{
+		{TestAB: TestAB{A: "a", B: "b"}, E: "e", F: "f"},
+	}
+	err := writer.Write(table, tablewriter.OptExcludeColumns("a", "F"))
+	assert.NoError(err)
+	assert.Equal("b,E\nb,e\n", buf.String())
+
+	// Columns passed to Write replace the columns passed to New, and can
+	// be passed more than once
+	buf.Reset()
+	writer = tablewriter.New(buf, tablewriter.OptHeader(), tablewriter.OptExcludeColumns("a"))
+	err = writer.Write(table, tablewriter.OptExcludeColumns("b"), tablewriter.OptExcludeColumns("F"))
+	assert.NoError(err)
+	assert.Equal("a,E\na,e\n", buf.String())
+}
+
+func Test_tablewriter_011(t *testing.T) {
+	assert := assert.New(t)
+	writer := tablewriter.New(new(strings.Builder))
+	err := writer.Write([]TestAB{{}}, tablewriter.OptColumns("a", "z"))
+	assert.Error(err)
+	err = writer.Write([]TestAB{{}}, tablewriter.OptExcludeColumns("z"))
+	assert.Error(err)
+}
+
+type TestOrder struct {
+	TestAB
+	E string `writer:",order:-1"`
+	F string `writer:",order:1"`
+}
+
+type TestPriority struct {
+	A string `writer:",width:10"`
+	B string `writer:",width:10,priority:1"`
+	C string `writer:",width:10,priority:2"`
+	D string `writer:",width:10"`
+}
+
+func Test_tablewriter_012(t *testing.T) {
+	assert := assert.New(t)
+	buf := new(strings.Builder)
+	writer := tablewriter.New(buf, tablewriter.OptHeader())
+	table := []TestOrder{
+		{TestAB: TestAB{A: "a", B: "b"}, E: "e", F: "f"},
+	}
+	err := writer.Write(table)
+	assert.NoError(err)
+	assert.Equal("E,a,b,F\ne,a,b,f\n", buf.String())
+
+	// Extreme orders do not overflow
+	buf.Reset()
+	err = writer.Write(struct {
+		A string `writer:",order:9223372036854775807"`
+		B string `writer:",order:-9223372036854775808"`
+	}{"a", "b"})
+	assert.NoError(err)
+	assert.Equal("B,A\nb,a\n", buf.String())
+}
+
+func Test_tablewriter_013(t *testing.T) {
+	assert := assert.New(t)
+	buf := new(strings.Builder)
+	writer := tablewriter.New(buf, tablewriter.OptOutputText(), tablewriter.OptHeader())
+	table := []TestPriority{
+		{A: "a", B: "b", C: "c", D: "d"},
+	}
+
+	// All columns fit
+	err := writer.Write(table, tablewriter.OptTableWidth(45))
+	assert.NoError(err)
+	assert.Equal("|A         |B         |C         |D         |\n|a         |b         |c         |d         |\n", buf.String())
+
+	// Column C is dropped first, then column B
+	buf.Reset()
+	err = writer.Write(table, tablewriter.OptTableWidth(40))
+	assert.NoError(err)
+	assert.Equal("|A         |B         |D         |\n|a         |b         |d         |\n", buf.String())
+
+	buf.Reset()
+	err = writer.Write(table, tablewriter.OptTableWidth(10))
+	assert.NoError(err)
+	assert.Equal("|A         |D         |\n|a         |d         |\n", buf.String())
+}
+
+type TestSort struct {
+	Name  string `writer:"name"`
+	Count int    `writer:"count,sort:desc"`
+}
+
+func Test_tablewriter_014(t *testing.T) {
+	assert := assert.New(t)
+	buf := new(strings.Builder)
+	writer := tablewriter.New(buf)
+	table := []TestSort{
+		{"b", 2}, {"a", 10}, {"c", 2},
+	}
+
+	// Sort by tag
+	err := writer.Write(table)
+	assert.NoError(err)
+	assert.Equal("a,10\nb,2\nc,2\n", buf.String())
+
+	// Sort by tag on a column which is not output
+	buf.Reset()
+	err = writer.Write(table, tablewriter.OptColumns("name"))
+	assert.NoError(err)
+	assert.Equal("a\nb\nc\n", buf.String())
+
+	buf.Reset()
+	err = writer.Write([]TestSort{{"b", 2}, {"c", 10}, {"a", 2}}, tablewriter.OptExcludeColumns("count"))
+	assert.NoError(err)
+	assert.Equal("c\nb\na\n", buf.String())
+
+	// Sort by option
+	buf.Reset()
+	err = writer.Write(table, tablewriter.OptSortBy("count", false), tablewriter.OptSortBy("name", true))
+	assert.NoError(err)
+	assert.Equal("c,2\nb,2\na,10\n", buf.String())
+
+	// Input order is preserved
+	assert.Equal("b", table[0].Name)
+
+	// Unknown column
+	err = writer.Write(table, tablewriter.OptSortBy("z", false))
+	assert.Error(err)
+}
+
+func Test_tablewriter_015(t *testing.T) {
+	assert := assert.New(t)
+	buf := new(strings.Builder)
+	writer := tablewriter.New(buf, tablewriter.OptSortBy("name", false))
+	table := []TestSort{
+		{Name: "file10"}, {Name: "File2"}, {Name: "file1"},
+	}
+
+	err := writer.Write(table, tablewriter.OptColumns("name"))
+	assert.NoError(err)
+	assert.Equal("File2\nfile1\nfile10\n", buf.String())
+
+	buf.Reset()
+	err = writer.Write(table, tablewriter.OptColumns("name"), tablewriter.OptSortStrings(true, true))
+	assert.NoError(err)
+	assert.Equal("file1\nFile2\nfile10\n", buf.String())
+
+	// Sort passed to Write replaces the sort passed to New
+	buf.Reset()
+	table = []TestSort{
+		{"a", 1}, {"b", 1}, {"a", 2},
+	}
+	err = writer.Write(table, tablewriter.OptSortBy("count", true), tablewriter.OptSortBy("name", true))
+	assert.NoError(err)
+	assert.Equal("a,2\nb,1\na,1\n", buf.String())
+}
+
+type TestFilter struct {
+	Name    string `writer:"name"`
+	Status  string `writer:"status"`
+	Retries int    `writer:"retries"`
+}
+
+func Test_tablewriter_016(t *testing.T) {
+	assert := assert.New(t)
+	buf := new(strings.Builder)
+	writer := tablewriter.New(buf)
+	table := []TestFilter{
+		{"a", "failed", 5}, {"b", "ok", 5}, {"c", "failed", 1},
+	}
+
+	err := writer.Write(table, tablewriter.OptFilter(`status == "failed" && retries > 3`))
+	assert.NoError(err)
+	assert.Equal("a,failed,5\n", buf.String())
+
+	// Parse error
+	err = writer.Write(table, tablewriter.OptFilter(`status ==`))
+	assert.Error(err)
+
+	// Unknown column
+	err = writer.Write(table, tablewriter.OptFilter(`missing == 1`))
+	assert.Error(err)
+
+	// Evaluation error
+	err = writer.Write(table, tablewriter.OptFilter(`status > 1`))
+	assert.Error(err)
+}
+
+type TestFooter struct {
+	Name  string  `writer:"name,width:5"`
+	Count int     `writer:"count,total:sum,width:5"`
+	Cost  float64 `writer:"cost,total:avg,width:5"`
+	Max   *int    `writer:"max,total:max,width:5"`
+}
+
+func Test_tablewriter_017(t *testing.T) {
+	assert := assert.New(t)
+	buf := new(strings.Builder)
+	writer := tablewriter.New(buf, tablewriter.OptFooter())
+	max := 3
+	table := []TestFooter{
+		{"a", 1, 1.5, nil}, {"b", 2, 2.5, &max},
+	}
+
+	err := writer.Write(table)
+	assert.NoError(err)
+	assert.Equal("a,1,1.5,<nil>\nb,2,2.5,3\n,3,2,3\n", buf.String())
+
+	buf.Reset()
+	err = writer.Write(table, tablewriter.OptOutputText())
+	assert.NoError(err)
+	assert.Equal("|a    |1    |1.5  |<nil>|\n|b    |2    |2.5  |3    |\n|-----|-----|-----|-----|\n|     |3    |2    |3    |\n", buf.String())
+}
+
+func Test_tablewriter_018(t *testing.T) {
+	assert := assert.New(t)
+	writer := tablewriter.New(new(strings.Builder), tablewriter.OptFooter())
+	err := writer.Write([]struct {
+		A string `writer:",total:sum"`
+	}{{"a"}})
+	assert.Error(err)
+	err = writer.Write([]struct {
+		A int `writer:",total:median"`
+	}{{1}})
+	assert.Error(err)
+}
+
+type TestGroup struct {
+	Team string `writer:"team,width:5"`
+	Name string `writer:"name,width:5"`
+	Cost int    `writer:"cost,width:5,total:sum"`
+}
+
+func Test_tablewriter_019(t *testing.T) {
+	assert := assert.New(t)
+	buf := new(strings.Builder)
+	writer := tablewriter.New(buf)
+	table := []TestGroup{
+		{"a", "x", 1}, {"a", "y", 2}, {"b", "z", 3},
+	}
+
+	// Repeated values are kept in CSV output
+	err := writer.Write(table, tablewriter.OptGroupBy("team", true))
+	assert.NoError(err)
+	assert.Equal("a,x,1\na,y,2\na,,3\nb,z,3\nb,,3\n", buf.String())
+
+	// Repeated values are left blank in text output
+	buf.Reset()
+	err = writer.Write(table, tablewriter.OptGroupBy("team", false), tablewriter.OptOutputText())
+	assert.NoError(err)
+	assert.Equal("|a    |x    |1    |\n|     |y    |2    |\n|b    |z    |3    |\n", buf.String())
+
+	// Subtotals and footer in text output
+	buf.Reset()
+	err = writer.Write(table, tablewriter.OptGroupBy("team", true), tablewriter.OptFooter(), tablewriter.OptOutputText())
+	assert.NoError(err)
+	assert.Equal(strings.Join([]string{
+		"|a    |x    |1    |",
+		"|     |y    |2    |",
+		"|-----|-----|-----|",
+		"|a    |     |3    |",
+		"|b    |z    |3    |",
+		"|-----|-----|-----|",
+		"|b    |     |3    |",
+		"|-----|-----|-----|",
+		"|     |     |6    |",
+	}, "\n")+"\n", buf.String())
+
+	// Unknown column
+	err = writer.Write(table, tablewriter.OptGroupBy("z", false))
+	assert.Error(err)
+}
+
+func Test_tablewriter_020(t *testing.T) {
+	assert := assert.New(t)
+	buf := new(strings.Builder)
+	writer := tablewriter.New(buf, tablewriter.OptOutputExpanded())
+	table := []TestFilter{
+		{"alice", "failed", 5}, {"bob", "ok", 10},
+	}
+
+	err := writer.Write(table)
+	assert.NoError(err)
+	assert.Equal(strings.Join([]string{
+		"-[ RECORD 1 ]-----------------",
+		"name    | alice",
+		"status  | failed",
+		"retries | 5",
+		"-[ RECORD 2 ]-----------------",
+		"name    | bob",
+		"status  | ok",
+		"retries | 10",
+	}, "\n")+"\n", buf.String())
+}
+
+func Test_tablewriter_021(t *testing.T) {
+	assert := assert.New(t)
+	buf := new(strings.Builder)
+	writer := tablewriter.New(buf, tablewriter.OptOutputExpanded(), tablewriter.OptFooter())
+	table := []TestGroup{
+		{"a", "the quick brown fox", 1}, {"b", "y", 2},
+	}
+
+	err := writer.Write(table[:1], tablewriter.OptColumns("name", "cost"))
+	assert.NoError(err)
+	assert.Equal(strings.Join([]string{
+		"-[ RECORD 1 ]-",
+		"name | the quick brown fox",
+		"cost | 1",
+		"-[ TOTAL ]--",
+		"name |",
+		"cost | 1",
+	}, "\n")+"\n", buf.String())
+
+	// Values are truncated to the table width
+	buf.Reset()
+	err = writer.Write(table[:1], tablewriter.OptColumns("name", "cost"), tablewriter.OptTableWidth(16))
+	assert.NoError(err)
+	assert.Equal(strings.Join([]string{
+		"-[ RECORD 1 ]---",
+		"name | the quick",
+		"cost | 1",
+		"-[ TOTAL ]------",
+		"name |",
+		"cost | 1",
+	}, "\n")+"\n", buf.String())
+}
+
+func Test_tablewriter_022(t *testing.T) {
+	assert := assert.New(t)
+	buf := new(strings.Builder)
+	writer := tablewriter.New(buf, tablewriter.OptOutputAuto())
+	table := []TestPriority{
+		{A: "a", B: "b", C: "c", D: "d"},
+	}
+
+	// Table fits
+	err := writer.Write(table, tablewriter.OptTableWidth(45))
+	assert.NoError(err)
+	assert.Equal("|a         |b         |c         |d         |\n", buf.String())
+
+	// Table fits when low-priority columns are dropped
+	buf.Reset()
+	err = writer.Write(table, tablewriter.OptTableWidth(40))
+	assert.NoError(err)
+	assert.Equal("|a         |b         |d         |\n", buf.String())
+
+	buf.Reset()
+	err = writer.Write(table, tablewriter.OptTableWidth(23))
+	assert.NoError(err)
+	assert.Equal("|a         |d         |\n", buf.String())
+
+	// Table does not fit, and all columns are output
+	buf.Reset()
+	err = writer.Write(table, tablewriter.OptTableWidth(22))
+	assert.NoError(err)
+	assert.Equal("-[ RECORD 1 ]---------\nA | a\nB | b\nC | c\nD | d\n", buf.String())
+}
+
+func Test_tablewriter_023(t *testing.T) {
+	assert := assert.New(t)
+	buf := new(strings.Builder)
+	writer := tablewriter.New(buf)
+	table := make([]TestSort, 1235)
+	for i := range table {
+		table[i] = TestSort{Name: "n", Count: i}
+	}
+
+	// Nothing is written in place of skipped rows in CSV output
+	err := writer.Write(table, tablewriter.OptLimit(2), tablewriter.OptColumns("count"))
+	assert.NoError(err)
+	assert.Equal("1234\n1233\n", buf.String())
+
+	buf.Reset()
+	err = writer.Write(table, tablewriter.OptLimitTail(1), tablewriter.OptColumns("count"))
+	assert.NoError(err)
+	assert.Equal("0\n", buf.String())
+
+	// A marker row is written in text output
+	buf.Reset()
+	err = writer.Write(table, tablewriter.OptLimitHeadTail(1), tablewriter.OptOutputText(), tablewriter.OptHeader())
+	assert.NoError(err)
+	assert.Equal(strings.Join([]string{
+		"|name                |count               |",
+		"|n                   |1234                |",
+		"|… 1,233 more rows                        |",
+		"|n                   |0                   |",
+	}, "\n")+"\n", buf.String())
+
+	// All rows are written when within the limit
+	buf.Reset()
+	err = writer.Write(table[:2], tablewriter.OptLimitHeadTail(1), tablewriter.OptOutputText())
+	assert.NoError(err)
+	assert.Equal("|n                   |1                   |\n|n                   |0                   |\n", buf.String())
+}
+
+func Test_tablewriter_024(t *testing.T) {
+	assert := assert.New(t)
+	buf := new(strings.Builder)
+	writer := tablewriter.New(buf, tablewriter.OptOutputText(), tablewriter.OptHeader())
+	table := []TestGroup{
+		{"a", "x", 1}, {"a", "y", 2}, {"b", "z", 3},
+	}
+
+	err := writer.Write(table, tablewriter.OptPageSize(2), tablewriter.OptPageFooter(), tablewriter.OptPageFormFeed())
+	assert.NoError(err)
+	assert.Equal(strings.Join([]string{
+		"|team |name |cost |",
+		"|a    |x    |1    |",
+		"|a    |y    |2    |",
+		"Page 1/2",
+		"\f|team |name |cost |",
+		"|b    |z    |3    |",
+		"Page 2/2",
+	}, "\n")+"\n", buf.String())
+
+	// Group values are repeated at the top of each page
+	buf.Reset()
+	err = writer.Write(table, tablewriter.OptPageSize(1), tablewriter.OptGroupBy("team", false))
+	assert.NoError(err)
+	assert.Equal(strings.Join([]string{
+		"|team |name |cost |",
+		"|a    |x    |1    |",
+		"|team |name |cost |",
+		"|a    |y    |2    |",
+		"|team |name |cost |",
+		"|b    |z    |3    |",
+	}, "\n")+"\n", buf.String())
+}
+
+func Test_tablewriter_025(t *testing.T) {
+	assert := assert.New(t)
+	buf := new(strings.Builder)
+	writer := tablewriter.New(buf, tablewriter.OptPager())
+	table := []TestAB{
+		{A: "hello", B: "world"},
+	}
+
+	// Output is written directly when not a terminal
+	err := writer.Write(table)
+	assert.NoError(err)
+	assert.Equal("hello,world\n", buf.String())
+	assert.Equal(buf, writer.Output())
+}
+
+type TestNumber struct {
+	Bytes     uint64  `writer:",bytes"`
+	SI        int     `writer:",si"`
+	Thousands int     `writer:",thousands"`
+	Precision float64 `writer:",precision:2"`
+	Percent   float32 `writer:",percent"`
+	Plain     float64
+}
+
+func Test_tablewriter_026(t *testing.T) {
+	assert := assert.New(t)
+	buf := new(strings.Builder)
+	writer := tablewriter.New(buf, tablewriter.OptDelimiter(';'))
+	table := []TestNumber{
+		{1610612736, 12345, -1234567, 3.14159, 0.125, 1.5},
+		{512, 999, 123, 2, 1, 2},
+		{1048575, 999999, 0, 0, 0, 0},
+		{1023, -999950, 0, 0, 0, 0},
+	}
+	err := writer.Write(table)
+	assert.NoError(err)
+	assert.Equal("1.5 GiB;12.3k;-1,234,567;3.14;12.5%;1.5\n512 B;999;123;2.00;100.0%;2\n1.0 MiB;1.0M;0;0.00;0.0%;0\n1023 B;-1.0M;0;0.00;0.0%;0\n", buf.String())
+}
+
+type TestDuration struct {
+	Duration time.Duration
+	Compact  time.Duration `writer:",compact"`
+	Time     time.Time     `writer:",ago"`
+}
+
+func Test_tablewriter_027(t *testing.T) {
+	assert := assert.New(t)
+	buf := new(strings.Builder)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	writer := tablewriter.New(buf, tablewriter.OptNow(now))
+	table := []TestDuration{
+		{90 * time.Second, 76*time.Hour + 30*time.Minute, now.Add(-5*time.Minute - 10*time.Second)},
+		{0, 250 * time.Millisecond, now.Add(2 * time.Hour)},
+		{time.Millisecond, 24*time.Hour + 5*time.Second, now},
+	}
+	err := writer.Write(table)
+	assert.NoError(err)
+	assert.Equal("1m30s,3d4h,5m ago\n0s,250ms,in 2h\n1ms,1d,now\n", buf.String())
+
+	// Only fields with the "ago" tag are relative, unless OptRelativeTime is set
+	mixed := struct {
+		Created time.Time
+		Seen    time.Time `writer:",ago"`
+	}{now.Add(-time.Hour), now.Add(-time.Minute)}
+	buf.Reset()
+	err = writer.Write(mixed, tablewriter.OptTimeLayout(time.DateTime, false))
+	assert.NoError(err)
+	assert.Equal("2024-05-01 11:00:00,1m ago\n", buf.String())
+
+	buf.Reset()
+	err = writer.Write(mixed, tablewriter.OptRelativeTime())
+	assert.NoError(err)
+	assert.Equal("1h ago,1m ago\n", buf.String())
+
+	// Relative times are rounded to the unit which is output
+	buf.Reset()
+	err = writer.Write([]TestDuration{
+		{Time: now.Add(2*time.Hour - time.Nanosecond)},
+		{Time: now.Add(-time.Hour - 59*time.Minute)},
+		{Time: now.Add(-59*time.Minute - 40*time.Second)},
+		{Time: now.Add(time.Hour + 20*time.Minute)},
+	}, tablewriter.OptColumns("Time"))
+	assert.NoError(err)
+	assert.Equal("in 2h\n2h ago\n1h ago\nin 1h\n", buf.String())
+
+	// Averages have the type of the column
+	buf.Reset()
+	err = writer.Write([]struct {
+		Duration time.Duration `writer:",total:avg"`
+		Count    int           `writer:",total:avg"`
+	}{{2 * time.Second, 1}, {3 * time.Second, 2}}, tablewriter.OptFooter())
+	assert.NoError(err)
+	assert.Equal("2s,1\n3s,2\n2.5s,2\n", buf.String())
+}
+
+type TestUnixTime struct {
+	Seconds uint32 `writer:",unixtime"`
+	Milli   int64  `writer:",unixmilli"`
+	Micro   *int64 `writer:",unixmicro"`
+	Nano    uint64 `writer:",unixnano"`
+}
+
+func Test_tablewriter_028(t *testing.T) {
+	assert := assert.New(t)
+	buf := new(strings.Builder)
+	writer := tablewriter.New(buf, tablewriter.OptTimeLayout(time.RFC3339, false), tablewriter.OptDelimiter(' '))
+	ts := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	micro := ts.UnixMicro()
+	table := []TestUnixTime{
+		{uint32(ts.Unix()), ts.UnixMilli(), &micro, uint64(ts.UnixNano())},
+		{0, 0, nil, 0},
+	}
+	err := writer.Write(table)
+	assert.NoError(err)
+	assert.Equal("2024-05-01T12:00:00Z 2024-05-01T12:00:00Z 2024-05-01T12:00:00Z 2024-05-01T12:00:00Z\n<nil> <nil> <nil> <nil>\n", buf.String())
+}
+
+type TestEnum int
+
+func (e TestEnum) String() string {
+	switch e {
+	case 1:
+		return "running"
+	default:
+		return "stopped"
+	}
+}
+
+type TestStringer struct {
+	Enum  TestEnum
+	Raw   TestEnum `writer:",raw"`
+	IP    net.IP
+	Err   error
+	Nil   error
+	Text  *big.Int
+	Bytes []byte
+}
+
+func Test_tablewriter_029(t *testing.T) {
+	assert := assert.New(t)
+	buf := new(strings.Builder)
+	writer := tablewriter.New(buf, tablewriter.OptDelimiter(' '))
+	table := []TestStringer{
+		{1, 1, net.IPv4(127, 0, 0, 1), errors.New("failed"), nil, big.NewInt(42), nil},
+	}
+	err := writer.Write(table)
+	assert.NoError(err)
+	assert.Equal("running 1 127.0.0.1 failed <nil> 42 <nil>\n", buf.String())
+
+	// Filter by the text of the enum
+	buf.Reset()
+	err = writer.Write(table, tablewriter.OptFilter(`Enum == "running" && Err == "failed"`), tablewriter.OptColumns("Enum"))
+	assert.NoError(err)
+	assert.Equal("running\n", buf.String())
+}
+
+type TestStatus int
+
+func (s TestStatus) MarshalTable(format tablewriter.Format, opts tablewriter.MarshalOpts) (any, error) {
+	switch format {
+	case tablewriter.FormatText:
+		return fmt.Sprintf("[%s:%d]", opts.Name, opts.Width), nil
+	default:
+		return int(s), nil
+	}
+}
+
+func (s TestStatus) Marshal() ([]byte, error) {
+	return []byte("marshal"), nil
+}
+
+type TestTableMarshaller struct {
+	Status TestStatus `writer:"status,width:12"`
+}
+
+func Test_tablewriter_030(t *testing.T) {
+	assert := assert.New(t)
+	buf := new(strings.Builder)
+	writer := tablewriter.New(buf)
+	table := []TestTableMarshaller{{Status: 3}}
+
+	err := writer.Write(table)
+	assert.NoError(err)
+	assert.Equal("3\n", buf.String())
+
+	buf.Reset()
+	err = writer.Write(table, tablewriter.OptOutputText())
+	assert.NoError(err)
+	assert.Equal("|[status:12] |\n", buf.String())
+}
+
+type TestUUID [16]byte
+
+type TestRegistry struct {
+	ID   TestUUID
+	IP   *net.IP
+	Enum TestEnum
+}
+
+func Test_tablewriter_031(t *testing.T) {
+	assert := assert.New(t)
+	registry := tablewriter.NewRegistry()
+	assert.NoError(registry.Register(reflect.TypeOf(TestUUID{}), func(v any) (any, error) {
+		id := v.(TestUUID)
+		return fmt.Sprintf("%x", id[:2]), nil
+	}))
+	assert.NoError(registry.Register(reflect.TypeOf(net.IP{}), func(v any) (any, error) {
+		return []byte("ip:" + v.(net.IP).String()), nil
+	}))
+	assert.NoError(registry.Register(reflect.TypeOf(TestEnum(0)), func(v any) (any, error) {
+		return int(v.(TestEnum)), nil
+	}))
+	assert.Error(registry.Register(reflect.TypeOf(TestEnum(0)), func(v any) (any, error) {
+		return nil, nil
+	}))
+
+	buf := new(strings.Builder)
+	writer := tablewriter.New(buf, tablewriter.OptRegistry(registry))
+	ip := net.IPv4(10, 0, 0, 1)
+	err := writer.Write(TestRegistry{ID: TestUUID{0xab, 0xcd}, IP: &ip, Enum: 1})
+	assert.NoError(err)
+	assert.Equal("abcd,ip:10.0.0.1,1\n", buf.String())
+
+	// Register a converter for a pointer type
+	assert.NoError(registry.Register(reflect.TypeOf((*big.Int)(nil)), func(v any) (any, error) {
+		return v.(*big.Int).Text(16), nil
+	}))
+	buf.Reset()
+	err = writer.Write(struct{ N *big.Int }{big.NewInt(255)})
+	assert.NoError(err)
+	assert.Equal("ff\n", buf.String())
+}
+
+type TestNull struct {
+	Name  string
+	Count *int      `writer:",null:-"`
+	Notes *string   `writer:",null"`
+	Login time.Time `writer:",null:never"`
+	Tags  []string
+	IP    net.IP
+}
+
+func Test_tablewriter_032(t *testing.T) {
+	assert := assert.New(t)
+	count, notes, login := 3, "hello", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	table := []TestNull{
+		{Name: "a"},
+		{Name: "b", Count: &count, Notes: &notes, Login: login, Tags: []string{"x"}, IP: net.IPv4(10, 0, 0, 1)},
+	}
+
+	// Tags set the nil value for each column, and pointers are output as values
+	buf := new(strings.Builder)
+	writer := tablewriter.New(buf, tablewriter.OptNull("NULL"), tablewriter.OptTimeLayout(time.DateOnly, false))
+	assert.NoError(writer.Write(table))
+	assert.Equal("a,-,,never,NULL,NULL\nb,3,hello,2024-01-02,\"[\"\"x\"\"]\",10.0.0.1\n", buf.String())
+
+	// The option takes precedence over the tag
+	buf.Reset()
+	assert.NoError(writer.Write(table[:1], tablewriter.OptColumnNull("Login", "n/a"), tablewriter.OptColumnNull("Tags", ""), tablewriter.OptColumnNull("IP", "-")))
+	assert.Equal("a,-,,n/a,,-\n", buf.String())
+
+	// Unknown columns are an error
+	assert.Error(writer.Write(table, tablewriter.OptColumnNull("Other", "-")))
+}
+
+type TestSecret struct {
+	User     string
+	Password string  `writer:",secret"`
+	Card     string  `writer:",mask:4"`
+	Token    string  `writer:",hash"`
+	Key      *string `writer:",secret"`
+}
+
+func Test_tablewriter_033(t *testing.T) {
+	assert := assert.New(t)
+	table := []TestSecret{
+		{User: "a", Password: "hunter2", Card: "4111111111111111", Token: "abc"},
+		{User: "b", Card: "123", Token: "abc"},
+	}
+
+	// Secret values are redacted, and hashes are stable
+	buf := new(strings.Builder)
+	writer := tablewriter.New(buf)
+	assert.NoError(writer.Write(table))
+	assert.Equal("a,****,****1111,ba7816bf,<nil>\nb,****,****,ba7816bf,<nil>\n", buf.String())
+
+	// Secret values are output with OptRevealSecrets
+	buf.Reset()
+	assert.NoError(writer.Write(table[0], tablewriter.OptRevealSecrets()))
+	assert.Equal("a,hunter2,4111111111111111,abc,<nil>\n", buf.String())
+}
+
+type TestDecimal struct {
+	Name   string   `writer:",width:4"`
+	Amount *float64 `writer:",decimal,width:8,null:-"`
+}
+
+func Test_tablewriter_034(t *testing.T) {
+	assert := assert.New(t)
+	a, b, c := 1.5, -12.25, 100.0
+	table := []TestDecimal{
+		{Name: "a", Amount: &a},
+		{Name: "b", Amount: &b},
+		{Name: "c", Amount: &c},
+		{Name: "d"},
+	}
+
+	// Decimal points line up, and other values are aligned right
+	buf := new(strings.Builder)
+	writer := tablewriter.New(buf, tablewriter.OptOutputText(), tablewriter.OptHeader())
+	assert.NoError(writer.Write(table))
+	assert.Equal(strings.Join([]string{
+		"|Name|  Amount|",
+		"|a   |    1.5 |",
+		"|b   |  -12.25|",
+		"|c   |  100   |",
+		"|d   |       -|",
+	}, "\n")+"\n", buf.String())
+}
+
+type TestAlign struct {
+	Name   string `writer:",width:6,center"`
+	Amount int    `writer:",width:8,right,header-align:center"`
+}
+
+func Test_tablewriter_035(t *testing.T) {
+	assert := assert.New(t)
+	buf := new(strings.Builder)
+	writer := tablewriter.New(buf, tablewriter.OptOutputText(), tablewriter.OptHeader())
+	assert.NoError(writer.Write([]TestAlign{{Name: "ab", Amount: 12}}))
+	assert.Equal("| Name | Amount |\n|  ab  |      12|\n", buf.String())
+}
+
+type TestTruncate struct {
+	Path string `writer:",width:8,truncate:middle"`
+	ID   string `writer:",width:6,truncate:start,ellipsis:..."`
+	Name string `writer:",width:4"`
+}
+
+func Test_tablewriter_036(t *testing.T) {
+	assert := assert.New(t)
+	table := TestTruncate{Path: "/usr/local/bin/go", ID: "0123456789", Name: "abcdef"}
+
+	// Truncation is set by tags
+	buf := new(strings.Builder)
+	writer := tablewriter.New(buf, tablewriter.OptOutputText())
+	assert.NoError(writer.Write(table))
+	assert.Equal("|/usr…/go|...789|abcd|\n", buf.String())
+
+	// Truncation is set for all columns by option, and tags take precedence
+	buf.Reset()
+	assert.NoError(writer.Write(table, tablewriter.OptTruncate("end", "…")))
+	assert.Equal("|/usr…/go|...789|abc…|\n", buf.String())
+
+	// Unknown modes are an error
+	assert.Error(writer.Write(table, tablewriter.OptTruncate("left", "…")))
+}
+
+type TestWrap struct {
+	URL string `writer:",width:10,wrap,maxlines:2"`
+}
+
+func Test_tablewriter_037(t *testing.T) {
+	assert := assert.New(t)
+	buf := new(strings.Builder)
+	writer := tablewriter.New(buf, tablewriter.OptOutputText())
+	assert.NoError(writer.Write(TestWrap{URL: "https://example.com/some/long/path"}))
+	assert.Equal("|https://  |\n|example.c…|\n", buf.String())
+}
+
+type TestMultiline struct {
+	Name        string `writer:",width:4"`
+	Description string `writer:",width:6,multiline"`
+}
+
+func Test_tablewriter_038(t *testing.T) {
+	assert := assert.New(t)
+	table := TestMultiline{Name: "a\nb", Description: "one\ntwo"}
+
+	// Newlines are line breaks in columns with the tag
+	buf := new(strings.Builder)
+	writer := tablewriter.New(buf, tablewriter.OptOutputText())
+	assert.NoError(writer.Write(table))
+	assert.Equal("|a\\nb|one   |\n|    |two   |\n", buf.String())
+
+	// Newlines are line breaks in all columns with the option
+	buf.Reset()
+	assert.NoError(writer.Write(table, tablewriter.OptMultiline()))
+	assert.Equal("|a   |one   |\n|b   |two   |\n", buf.String())
+}
+
+func Test_tablewriter_039(t *testing.T) {
+	assert := assert.New(t)
+	table := TestAB{A: "a\u200bb", B: "c\u202ed"}
+
+	// Non-printable runes are escaped by default
+	buf := new(strings.Builder)
+	writer := tablewriter.New(buf, tablewriter.OptOutputText(), tablewriter.OptDelimiter(' '))
+	assert.NoError(writer.Write(table))
+	assert.Equal(` a\u200bb             c\u202ed             `+"\n", buf.String())
+
+	// Non-printable runes are stripped
+	buf.Reset()
+	assert.NoError(writer.Write(table, tablewriter.OptEscape("strip")))
+	assert.Equal(" ab                   cd                   \n", buf.String())
+
+	// Unknown policies are an error
+	assert.Error(writer.Write(table, tablewriter.OptEscape("other")))
+}
+
+func Test_tablewriter_040(t *testing.T) {
+	assert := assert.New(t)
+	buf := new(strings.Builder)
+	writer := tablewriter.New(buf, tablewriter.OptFooter())
+	table := []TestGroup{
+		{"a", "x", 1}, {"a", "y", 2}, {"b", "x", 3}, {"b", "y", 4}, {"c", "x", 5},
+	}
+
+	// Footer includes skipped rows
+	err := writer.Write(table, tablewriter.OptLimit(2))
+	assert.NoError(err)
+	assert.Equal("a,x,1\na,y,2\n,,15\n", buf.String())
+
+	// Subtotals are written for groups without skipped rows
+	buf.Reset()
+	err = writer.Write(table, tablewriter.OptLimit(2), tablewriter.OptGroupBy("team", true))
+	assert.NoError(err)
+	assert.Equal("a,x,1\na,y,2\na,,3\n,,15\n", buf.String())
+
+	// Subtotals are not written for groups which cross the marker
+	buf.Reset()
+	err = writer.Write(table, tablewriter.OptLimitHeadTail(1), tablewriter.OptGroupBy("team", true), tablewriter.OptOutputText())
+	assert.NoError(err)
+	assert.Equal(strings.Join([]string{
+		"|a    |x    |1    |",
+		"|… 3 more rows    |",
+		"|c    |x    |5    |",
+		"|-----|-----|-----|",
+		"|c    |     |5    |",
+		"|-----|-----|-----|",
+		"|     |     |15   |",
+	}, "\n")+"\n", buf.String())
+
+	// The group value is written in the row after the marker
+	buf.Reset()
+	err = writer.Write(table[:4], tablewriter.OptLimitHeadTail(1), tablewriter.OptGroupBy("team", false), tablewriter.OptOutputText())
+	assert.NoError(err)
+	assert.Equal(strings.Join([]string{
+		"|a    |x    |1    |",
+		"|… 2 more rows    |",
+		"|b    |y    |4    |",
+		"|-----|-----|-----|",
+		"|     |     |10   |",
+	}, "\n")+"\n", buf.String())
+}